@@ -0,0 +1,248 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload // import "github.com/YuriyLisovskiy/qrcode/payload"
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+// Maximum amount of bitcoins in satoshis.
+const maxBitcoinAmount = 21000000 * 100000000
+
+// Maximum length of a segwit address.
+const maxBech32Length = 90
+
+// Alphabets of Bech32 and Base58 encodings.
+const (
+	bech32Charset  = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
+
+// Checksum constants of Bech32 (BIP 173) and Bech32m (BIP 350).
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// Human-readable parts of segwit addresses of the main and test networks.
+var bech32Prefixes = map[string]bool{"bc": true, "tb": true, "bcrt": true}
+
+// Version bytes of P2PKH and P2SH addresses of the main and test networks.
+var base58Versions = map[byte]bool{0x00: true, 0x05: true, 0x6f: true, 0xc4: true}
+
+// Describes a bitcoin payment request, encoded as a BIP 21 URI:
+//
+//	bitcoin:BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4?amount=0.001&label=Acme
+type BitcoinPayment struct {
+	// Segwit (Bech32 or Bech32m) or legacy (Base58Check) address.
+	Address string
+
+	// Amount in satoshis. Zero leaves the amount to the payer.
+	Amount int64
+
+	// Label of the recipient and message describing the payment.
+	Label, Message string
+
+	// Optional BOLT 11 Lightning invoice paying the same amount.
+	Lightning string
+}
+
+// Returns an error if the address or the invoice has an invalid checksum, or the amount is out of range.
+func (b BitcoinPayment) Validate() error {
+	if err := validateBitcoinAddress(b.Address); err != nil {
+		return payloadErr("BitcoinPayment.Validate", err.Error())
+	}
+	if b.Amount < 0 || b.Amount > maxBitcoinAmount {
+		return payloadErr("BitcoinPayment.Validate", "amount must be in range [0.00000001, 21000000] BTC")
+	}
+	if b.Lightning != "" {
+		hrp, _, _, ok := decodeBech32(b.Lightning, 0)
+		if !ok || !strings.HasPrefix(hrp, "ln") {
+			return payloadErr("BitcoinPayment.Validate", fmt.Sprintf("invalid Lightning invoice '%s'", b.Lightning))
+		}
+	}
+	return nil
+}
+
+// Returns the BIP 21 URI of the payment. Segwit addresses and Lightning invoices,
+// which are case insensitive, are written in uppercase together with the scheme,
+// so they can be encoded in the compact alphanumeric mode.
+func (b BitcoinPayment) Payload() (string, error) {
+	if err := b.Validate(); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if _, _, _, ok := decodeBech32(b.Address, maxBech32Length); ok {
+		buf.WriteString("BITCOIN:" + strings.ToUpper(b.Address))
+	} else {
+		buf.WriteString("bitcoin:" + b.Address)
+	}
+	var params []string
+	if b.Amount != 0 {
+		amount := fmt.Sprintf("%d.%08d", b.Amount/100000000, b.Amount%100000000)
+		params = append(params, "amount="+strings.TrimSuffix(strings.TrimRight(amount, "0"), "."))
+	}
+	if b.Label != "" {
+		params = append(params, "label="+queryEscape(b.Label))
+	}
+	if b.Message != "" {
+		params = append(params, "message="+queryEscape(b.Message))
+	}
+	if b.Lightning != "" {
+		params = append(params, "lightning="+strings.ToUpper(b.Lightning))
+	}
+	if len(params) > 0 {
+		buf.WriteString("?" + strings.Join(params, "&"))
+	}
+	return buf.String(), nil
+}
+
+// Returns a QR Code of the payment URI. Payment requests are usually shown
+// on screens, so the medium error correction level is enough. The uppercase
+// part of the URI is encoded in the alphanumeric mode even if parameters follow it.
+func (b BitcoinPayment) Encode() (qr.Generator, error) {
+	return encodeMixedPayload(b.Payload, qr.EccMedium)
+}
+
+// Returns an error if address is neither a valid segwit nor a valid legacy address.
+func validateBitcoinAddress(address string) error {
+	if address == "" {
+		return fmt.Errorf("address is empty")
+	}
+	if hrp, version, program, ok := decodeBech32(address, maxBech32Length); ok {
+		if !bech32Prefixes[hrp] {
+			return fmt.Errorf("unknown network of address '%s'", address)
+		}
+		if version > 16 || len(program) < 2 || len(program) > 40 || version == 0 && len(program) != 20 && len(program) != 32 {
+			return fmt.Errorf("invalid witness program of address '%s'", address)
+		}
+		return nil
+	}
+	decoded, ok := decodeBase58Check(address)
+	if !ok || len(decoded) != 21 || !base58Versions[decoded[0]] {
+		return fmt.Errorf("invalid address '%s'", address)
+	}
+	return nil
+}
+
+// Decodes a segwit address or a Lightning invoice. Returns the human-readable
+// part, the witness version and the program converted to 8-bit bytes, which are
+// left empty for invoices. Returns
+// false if the string is mixed case, longer than maxLength (if positive), or the
+// checksum is invalid. Witness version 0 requires Bech32, later versions Bech32m.
+func decodeBech32(s string, maxLength int) (hrp string, version int, program []byte, ok bool) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s || maxLength > 0 && len(s) > maxLength {
+		return "", 0, nil, false
+	}
+	s = strings.ToLower(s)
+	separator := strings.LastIndex(s, "1")
+	if separator < 1 || separator+7 > len(s) {
+		return "", 0, nil, false
+	}
+	hrp = s[:separator]
+	data := make([]int, 0, len(s)-separator-1)
+	for _, c := range s[separator+1:] {
+		value := strings.IndexRune(bech32Charset, c)
+		if value == -1 {
+			return "", 0, nil, false
+		}
+		data = append(data, value)
+	}
+	checksum := bech32Polymod(append(bech32ExpandHrp(hrp), data...))
+	if strings.HasPrefix(hrp, "ln") {
+		// Lightning invoices use Bech32 and start with a timestamp, not a witness version.
+		return hrp, 0, nil, checksum == bech32Const
+	}
+	// A witness version and a checksum of 6 values at least.
+	if len(data) < 7 {
+		return "", 0, nil, false
+	}
+	version = data[0]
+	if version == 0 && checksum != bech32Const || version != 0 && checksum != bech32mConst {
+		return "", 0, nil, false
+	}
+	program, ok = convertBits(data[1:len(data)-6], 5, 8)
+	return hrp, version, program, ok
+}
+
+// Returns the checksum of Bech32 values.
+func bech32Polymod(values []int) int {
+	generator := [5]int{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	checksum := 1
+	for _, value := range values {
+		top := checksum >> 25
+		checksum = (checksum&0x1ffffff)<<5 ^ value
+		for i, g := range generator {
+			if (top>>uint(i))&1 == 1 {
+				checksum ^= g
+			}
+		}
+	}
+	return checksum
+}
+
+// Returns the human-readable part expanded for the checksum computation.
+func bech32ExpandHrp(hrp string) []int {
+	result := make([]int, 0, len(hrp)*2+1)
+	for _, c := range hrp {
+		result = append(result, int(c)>>5)
+	}
+	result = append(result, 0)
+	for _, c := range hrp {
+		result = append(result, int(c)&31)
+	}
+	return result
+}
+
+// Regroups values of from bits into bytes of to bits. Incomplete groups
+// at the end have to be zero padding shorter than from bits.
+func convertBits(values []int, from, to uint) ([]byte, bool) {
+	var result []byte
+	accumulator, bits := 0, uint(0)
+	for _, value := range values {
+		accumulator = accumulator<<from | value
+		bits += from
+		for bits >= to {
+			bits -= to
+			result = append(result, byte(accumulator>>bits&(1<<to-1)))
+		}
+	}
+	return result, bits < from && accumulator&(1<<bits-1) == 0
+}
+
+// Decodes a Base58Check string and returns the payload without the checksum.
+// Returns false if the string has invalid characters or the checksum does not match.
+func decodeBase58Check(s string) ([]byte, bool) {
+	value := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range s {
+		digit := strings.IndexRune(base58Alphabet, c)
+		if digit == -1 {
+			return nil, false
+		}
+		value.Mul(value, radix).Add(value, big.NewInt(int64(digit)))
+	}
+	decoded := value.Bytes()
+	// Every leading '1' stands for a zero byte.
+	for _, c := range s {
+		if c != '1' {
+			break
+		}
+		decoded = append([]byte{0}, decoded...)
+	}
+	if len(decoded) < 5 {
+		return nil, false
+	}
+	payload, checksum := decoded[:len(decoded)-4], decoded[len(decoded)-4:]
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	return payload, bytes.Equal(second[:4], checksum)
+}
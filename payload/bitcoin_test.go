@@ -0,0 +1,122 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+// Examples of BIP 173, BIP 350 and BOLT 11.
+const (
+	testSegwitAddress    = "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"
+	testTaprootAddress   = "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"
+	testLegacyAddress    = "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"
+	testLightningInvoice = "lnbc1pvjluezpp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqypqdpl2pkx2ctnv5sxxmmwwd5kgetjypeh2ursdae8g6twvus8g6rfwvs8qun0dfjkxaq8rkx3yf5tcsyz3d73gafnh3cax9rn449d9p5uxz9ezhhypd0elx87sjle52x86fux2ypatgddc6k63n7erqz25le42c4u4ecky03ylcqca784w"
+)
+
+var BitcoinPaymentPayload_TestData = []struct {
+	payment  BitcoinPayment
+	expected string
+}{
+	{
+		payment:  BitcoinPayment{Address: testSegwitAddress},
+		expected: "BITCOIN:" + strings.ToUpper(testSegwitAddress),
+	},
+	{
+		payment:  BitcoinPayment{Address: testLegacyAddress, Amount: 2030000000, Label: "Luke-Jr", Message: "Donation for project xyz"},
+		expected: "bitcoin:" + testLegacyAddress + "?amount=20.3&label=Luke-Jr&message=Donation%20for%20project%20xyz",
+	},
+	{
+		payment:  BitcoinPayment{Address: testTaprootAddress, Amount: 1, Lightning: testLightningInvoice},
+		expected: "BITCOIN:" + strings.ToUpper(testTaprootAddress) + "?amount=0.00000001&lightning=" + strings.ToUpper(testLightningInvoice),
+	},
+	{
+		payment:  BitcoinPayment{Address: "2MzQwSSnBHWHqSAqtTVQ6v47XtaisrJa1Vc", Amount: 100000000},
+		expected: "bitcoin:2MzQwSSnBHWHqSAqtTVQ6v47XtaisrJa1Vc?amount=1",
+	},
+}
+
+func Test_BitcoinPaymentPayload(test *testing.T) {
+	for i, data := range BitcoinPaymentPayload_TestData {
+		if actual, err := data.payment.Payload(); err != nil || actual != data.expected {
+			test.Errorf(
+				"bitcoin.Test_BitcoinPaymentPayload[%d]:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+}
+
+func Test_BitcoinPaymentEncode(test *testing.T) {
+	// The uppercase URI is encoded in the alphanumeric mode, which takes a smaller symbol.
+	for i, payment := range []BitcoinPayment{
+		{Address: testSegwitAddress},
+		{Address: testSegwitAddress, Amount: 100000, Label: "Acme"},
+	} {
+		gen, err := payment.Encode()
+		if err != nil {
+			test.Fatalf("bitcoin.Test_BitcoinPaymentEncode[%d]:\n\tunexpected error -> %s", i, err)
+		}
+		payload, _ := payment.Payload()
+		lower := qr.Generator{}
+		version, _ := lower.TextVersion(strings.ToLower(payload), qr.EccMedium)
+		if actual := (len(gen.GetModules()) - 17) / 4; actual >= version {
+			test.Errorf(
+				"bitcoin.Test_BitcoinPaymentEncode[%d]:\n\tactual version -> %d\n is not less than\n\tbyte mode version -> %d",
+				i, actual, version,
+			)
+		}
+	}
+}
+
+var BitcoinPaymentValidate_TestData = []struct {
+	payment  BitcoinPayment
+	expected string
+}{
+	{payment: BitcoinPayment{}, expected: "address is empty"},
+	{
+		payment:  BitcoinPayment{Address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5"},
+		expected: "invalid address 'bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5'",
+	},
+	{
+		// Witness version 1 with a Bech32 instead of a Bech32m checksum.
+		payment:  BitcoinPayment{Address: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd"},
+		expected: "invalid address 'bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd'",
+	},
+	{
+		payment:  BitcoinPayment{Address: "Bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		expected: "invalid address 'Bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4'",
+	},
+	{
+		payment:  BitcoinPayment{Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3"},
+		expected: "invalid address '1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3'",
+	},
+	{
+		// Valid Bech32m and Bech32 strings without data (BIP 350 and BIP 173 test vectors).
+		payment:  BitcoinPayment{Address: "a1lqfn3a"},
+		expected: "invalid address 'a1lqfn3a'",
+	},
+	{payment: BitcoinPayment{Address: "A12UEL5L"}, expected: "invalid address 'A12UEL5L'"},
+	{payment: BitcoinPayment{Address: testLegacyAddress, Amount: -1}, expected: "amount must be in range [0.00000001, 21000000] BTC"},
+	{
+		payment:  BitcoinPayment{Address: testLegacyAddress, Lightning: testSegwitAddress},
+		expected: "invalid Lightning invoice '" + testSegwitAddress + "'",
+	},
+}
+
+func Test_BitcoinPaymentValidate(test *testing.T) {
+	for i, data := range BitcoinPaymentValidate_TestData {
+		expected := payloadErr("BitcoinPayment.Validate", data.expected)
+		if actual := data.payment.Validate(); actual == nil || actual.Error() != expected.Error() {
+			test.Errorf(
+				"bitcoin.Test_BitcoinPaymentValidate[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, expected,
+			)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload // import "github.com/YuriyLisovskiy/qrcode/payload"
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+// Maximum length of a vCard or iCalendar content line in octets, excluding the line break.
+const maxContentLineLength = 75
+
+// Error correction level of contact codes, which are usually printed on business cards.
+const contactEcc = qr.EccMedium
+
+// Describes a phone number of a contact.
+type ContactPhone struct {
+	// Type of the number, e.g. "cell", "work", "home", "fax", may be empty.
+	Type string
+
+	Number string
+}
+
+// Describes a postal address of a contact.
+type ContactAddress struct {
+	// Type of the address, e.g. "work" or "home", may be empty.
+	Type string
+
+	POBox, Extended, Street, Locality, Region, PostalCode, Country string
+}
+
+// Describes a contact which can be encoded as a vCard or MECARD.
+type Contact struct {
+	FamilyName, GivenName, AdditionalNames, Prefix, Suffix string
+
+	Organization string
+
+	Phones    []ContactPhone
+	Emails    []string
+	Addresses []ContactAddress
+
+	URL  string
+	Note string
+}
+
+// Returns an error if the contact has neither a name nor an organization,
+// or has an empty phone number or email.
+func (c Contact) Validate() error {
+	if c.FamilyName == "" && c.GivenName == "" && c.Organization == "" {
+		return payloadErr("Contact.Validate", "contact has neither a name nor an organization")
+	}
+	for i, phone := range c.Phones {
+		if phone.Number == "" {
+			return payloadErr("Contact.Validate", fmt.Sprintf("phone %d has no number", i))
+		}
+	}
+	for i, email := range c.Emails {
+		if email == "" {
+			return payloadErr("Contact.Validate", fmt.Sprintf("email %d is empty", i))
+		}
+	}
+	return nil
+}
+
+// Returns the contact as a vCard 3.0 (RFC 2426).
+func (c Contact) VCard3() (string, error) {
+	return c.vCard("3.0")
+}
+
+// Returns the contact as a vCard 4.0 (RFC 6350).
+func (c Contact) VCard4() (string, error) {
+	return c.vCard("4.0")
+}
+
+// Returns the contact in the compact MECARD format. MECARD has no fields for
+// additional names, name prefixes and suffixes, phone and address types,
+// so they are left out.
+func (c Contact) MeCard() (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("MECARD:")
+	name := escapeField(c.FamilyName)
+	if name != "" && c.GivenName != "" {
+		name += ","
+	}
+	name += escapeField(c.GivenName)
+	if name != "" {
+		fmt.Fprintf(&buf, "N:%s;", name)
+	}
+	if c.Organization != "" {
+		fmt.Fprintf(&buf, "ORG:%s;", escapeField(c.Organization))
+	}
+	for _, phone := range c.Phones {
+		fmt.Fprintf(&buf, "TEL:%s;", escapeField(phone.Number))
+	}
+	for _, email := range c.Emails {
+		fmt.Fprintf(&buf, "EMAIL:%s;", escapeField(email))
+	}
+	for _, a := range c.Addresses {
+		parts := []string{a.POBox, a.Extended, a.Street, a.Locality, a.Region, a.PostalCode, a.Country}
+		for i := range parts {
+			parts[i] = escapeField(parts[i])
+		}
+		fmt.Fprintf(&buf, "ADR:%s;", strings.Join(parts, ","))
+	}
+	if c.URL != "" {
+		fmt.Fprintf(&buf, "URL:%s;", escapeField(c.URL))
+	}
+	if c.Note != "" {
+		fmt.Fprintf(&buf, "NOTE:%s;", escapeField(c.Note))
+	}
+	buf.WriteString(";")
+	return buf.String(), nil
+}
+
+// Returns the contact as a vCard 3.0, or as a MECARD if the vCard would
+// require a larger symbol, as computed by the encoder.
+func (c Contact) Payload() (string, error) {
+	vCard, err := c.VCard3()
+	if err != nil {
+		return "", err
+	}
+	meCard, err := c.MeCard()
+	if err != nil {
+		return "", err
+	}
+	gen := qr.Generator{}
+	meCardVersion, err := gen.TextVersion(meCard, contactEcc)
+	if err != nil {
+		return "", err
+	}
+	if vCardVersion, err := gen.TextVersion(vCard, contactEcc); err != nil || vCardVersion > meCardVersion {
+		return meCard, nil
+	}
+	return vCard, nil
+}
+
+// Returns a QR Code of the payload returned by Payload.
+func (c Contact) Encode() (qr.Generator, error) {
+	return encodePayload(c.Payload, contactEcc)
+}
+
+// Returns the contact as a vCard of the given version.
+func (c Contact) vCard(version string) (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+	v4 := version == "4.0"
+	var buf bytes.Buffer
+	line := func(format string, args ...interface{}) {
+		buf.WriteString(foldLine(fmt.Sprintf(format, args...)))
+	}
+	line("BEGIN:VCARD")
+	line("VERSION:%s", version)
+	line("N:%s", joinComponents(c.FamilyName, c.GivenName, c.AdditionalNames, c.Prefix, c.Suffix))
+	var fullName []string
+	for _, part := range []string{c.Prefix, c.GivenName, c.AdditionalNames, c.FamilyName, c.Suffix} {
+		if part != "" {
+			fullName = append(fullName, part)
+		}
+	}
+	if len(fullName) == 0 {
+		fullName = []string{c.Organization}
+	}
+	line("FN:%s", escapeText(strings.Join(fullName, " ")))
+	if c.Organization != "" {
+		line("ORG:%s", escapeText(c.Organization))
+	}
+	for _, phone := range c.Phones {
+		switch {
+		case v4:
+			line("TEL;VALUE=uri%s:tel:%s", vCardType(phone.Type, true), strings.Replace(phone.Number, " ", "-", -1))
+		default:
+			line("TEL%s:%s", vCardType(phone.Type, false), escapeText(phone.Number))
+		}
+	}
+	for _, email := range c.Emails {
+		if v4 {
+			line("EMAIL:%s", escapeText(email))
+		} else {
+			line("EMAIL;TYPE=INTERNET:%s", escapeText(email))
+		}
+	}
+	for _, a := range c.Addresses {
+		line("ADR%s:%s", vCardType(a.Type, v4),
+			joinComponents(a.POBox, a.Extended, a.Street, a.Locality, a.Region, a.PostalCode, a.Country))
+	}
+	if c.URL != "" {
+		line("URL:%s", c.URL)
+	}
+	if c.Note != "" {
+		line("NOTE:%s", escapeText(c.Note))
+	}
+	line("END:VCARD")
+	return buf.String(), nil
+}
+
+// Returns the TYPE parameter of a vCard property, lowercase in vCard 4.0
+// and uppercase in vCard 3.0, or nothing if the type is empty.
+func vCardType(t string, v4 bool) string {
+	if t == "" {
+		return ""
+	}
+	if v4 {
+		return ";TYPE=" + strings.ToLower(t)
+	}
+	return ";TYPE=" + strings.ToUpper(t)
+}
+
+// Escapes every component and joins them into a structured vCard value.
+func joinComponents(components ...string) string {
+	for i := range components {
+		components[i] = escapeText(components[i])
+	}
+	return strings.Join(components, ";")
+}
+
+// Escapes backslashes, commas, semicolons and line breaks of a vCard
+// or iCalendar text value.
+func escapeText(s string) string {
+	s = strings.Replace(s, "\\", "\\\\", -1)
+	s = strings.Replace(s, ",", "\\,", -1)
+	s = strings.Replace(s, ";", "\\;", -1)
+	s = strings.Replace(s, "\r\n", "\\n", -1)
+	return strings.Replace(s, "\n", "\\n", -1)
+}
+
+// Returns a vCard or iCalendar content line terminated with CRLF and folded into
+// lines of at most 75 octets, continuation lines start with a space. UTF-8
+// sequences are never split.
+func foldLine(s string) string {
+	var buf bytes.Buffer
+	limit := maxContentLineLength
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		buf.WriteString(s[:cut])
+		buf.WriteString("\r\n ")
+		s = s[cut:]
+		// The leading space of a continuation line counts towards its length.
+		limit = maxContentLineLength - 1
+	}
+	buf.WriteString(s)
+	buf.WriteString("\r\n")
+	return buf.String()
+}
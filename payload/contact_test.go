@@ -0,0 +1,159 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+var testContact = Contact{
+	FamilyName:   "Doe",
+	GivenName:    "John",
+	Prefix:       "Dr.",
+	Organization: "Acme, Inc.",
+	Phones:       []ContactPhone{{Type: "cell", Number: "+1 555 0100"}, {Number: "+1 555 0199"}},
+	Emails:       []string{"john@acme.com"},
+	Addresses: []ContactAddress{
+		{Type: "work", Street: "1 Main St; Suite 2", Locality: "Springfield", PostalCode: "12345", Country: "USA"},
+	},
+	URL:  "https://acme.com",
+	Note: "Line1\nLine2",
+}
+
+func Test_ContactVCard3(test *testing.T) {
+	expected := "BEGIN:VCARD\r\n" +
+		"VERSION:3.0\r\n" +
+		"N:Doe;John;;Dr.;\r\n" +
+		"FN:Dr. John Doe\r\n" +
+		"ORG:Acme\\, Inc.\r\n" +
+		"TEL;TYPE=CELL:+1 555 0100\r\n" +
+		"TEL:+1 555 0199\r\n" +
+		"EMAIL;TYPE=INTERNET:john@acme.com\r\n" +
+		"ADR;TYPE=WORK:;;1 Main St\\; Suite 2;Springfield;;12345;USA\r\n" +
+		"URL:https://acme.com\r\n" +
+		"NOTE:Line1\\nLine2\r\n" +
+		"END:VCARD\r\n"
+	if actual, err := testContact.VCard3(); err != nil || actual != expected {
+		test.Errorf("contact.Test_ContactVCard3:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q", actual, err, expected)
+	}
+}
+
+func Test_ContactVCard4(test *testing.T) {
+	expected := "BEGIN:VCARD\r\n" +
+		"VERSION:4.0\r\n" +
+		"N:Doe;John;;Dr.;\r\n" +
+		"FN:Dr. John Doe\r\n" +
+		"ORG:Acme\\, Inc.\r\n" +
+		"TEL;VALUE=uri;TYPE=cell:tel:+1-555-0100\r\n" +
+		"TEL;VALUE=uri:tel:+1-555-0199\r\n" +
+		"EMAIL:john@acme.com\r\n" +
+		"ADR;TYPE=work:;;1 Main St\\; Suite 2;Springfield;;12345;USA\r\n" +
+		"URL:https://acme.com\r\n" +
+		"NOTE:Line1\\nLine2\r\n" +
+		"END:VCARD\r\n"
+	if actual, err := testContact.VCard4(); err != nil || actual != expected {
+		test.Errorf("contact.Test_ContactVCard4:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q", actual, err, expected)
+	}
+}
+
+func Test_ContactMeCard(test *testing.T) {
+	expected := "MECARD:N:Doe,John;ORG:Acme\\, Inc.;TEL:+1 555 0100;TEL:+1 555 0199;EMAIL:john@acme.com;" +
+		"ADR:,,1 Main St\\; Suite 2,Springfield,,12345,USA;URL:https\\://acme.com;NOTE:Line1\nLine2;;"
+	if actual, err := testContact.MeCard(); err != nil || actual != expected {
+		test.Errorf("contact.Test_ContactMeCard:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q", actual, err, expected)
+	}
+	for _, data := range []struct {
+		contact  Contact
+		expected string
+	}{
+		{contact: Contact{Organization: "Acme"}, expected: "MECARD:ORG:Acme;;"},
+		{contact: Contact{GivenName: "John"}, expected: "MECARD:N:John;;"},
+		{contact: Contact{FamilyName: "Doe"}, expected: "MECARD:N:Doe;;"},
+	} {
+		if actual, _ := data.contact.MeCard(); actual != data.expected {
+			test.Errorf("contact.Test_ContactMeCard:\n\tactual -> %q\n is not equal to\n\texpected -> %q", actual, data.expected)
+		}
+	}
+}
+
+var ContactValidate_TestData = []struct {
+	contact  Contact
+	expected string
+}{
+	{contact: Contact{Note: "x"}, expected: "contact has neither a name nor an organization"},
+	{contact: Contact{GivenName: "x", Phones: []ContactPhone{{Type: "cell"}}}, expected: "phone 0 has no number"},
+	{contact: Contact{GivenName: "x", Emails: []string{"a@b.c", ""}}, expected: "email 1 is empty"},
+}
+
+func Test_ContactValidate(test *testing.T) {
+	for i, data := range ContactValidate_TestData {
+		expected := payloadErr("Contact.Validate", data.expected)
+		if actual := data.contact.Validate(); actual == nil || actual.Error() != expected.Error() {
+			test.Errorf(
+				"contact.Test_ContactValidate[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, expected,
+			)
+		}
+		if _, err := data.contact.Payload(); err == nil {
+			test.Errorf("contact.Test_ContactValidate[%d]:\n\tinvalid contact is encoded", i)
+		}
+	}
+}
+
+func Test_ContactPayload(test *testing.T) {
+	// A vCard needs a larger symbol than a MECARD.
+	meCard, _ := testContact.MeCard()
+	if actual, err := testContact.Payload(); err != nil || actual != meCard {
+		test.Errorf("contact.Test_ContactPayload:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q", actual, err, meCard)
+	}
+	// Both fit into a symbol of version 30.
+	contact := Contact{GivenName: "A", Note: strings.Repeat("x", 1250)}
+	vCard, _ := contact.VCard3()
+	if actual, err := contact.Payload(); err != nil || actual != vCard {
+		test.Errorf("contact.Test_ContactPayload:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q", actual, err, vCard)
+	}
+	gen, err := testContact.Encode()
+	if err != nil {
+		test.Fatalf("contact.Test_ContactPayload:\n\tunexpected error -> %s", err)
+	}
+	expected := qr.Generator{}
+	version, _ := expected.TextVersion(meCard, contactEcc)
+	if actual := len(gen.GetModules()); actual != version*4+17 {
+		test.Errorf("contact.Test_ContactPayload:\n\tactual size -> %d\n is not equal to\n\texpected -> %d", actual, version*4+17)
+	}
+}
+
+var foldLine_TestData = []struct {
+	line     string
+	expected string
+}{
+	{line: "FN:John", expected: "FN:John\r\n"},
+	{
+		line:     "NOTE:" + strings.Repeat("a", 70) + strings.Repeat("b", 74) + "c",
+		expected: "NOTE:" + strings.Repeat("a", 70) + "\r\n " + strings.Repeat("b", 74) + "\r\n c\r\n",
+	},
+	{
+		line:     "NOTE:" + strings.Repeat("a", 69) + "ЖЖ",
+		expected: "NOTE:" + strings.Repeat("a", 69) + "\r\n ЖЖ\r\n",
+	},
+}
+
+func Test_foldLine(test *testing.T) {
+	for i, data := range foldLine_TestData {
+		actual := foldLine(data.line)
+		if actual != data.expected {
+			test.Errorf("contact.Test_foldLine[%d]:\n\tactual -> %q\n is not equal to\n\texpected -> %q", i, actual, data.expected)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(actual, "\r\n"), "\r\n") {
+			if len(line) > maxContentLineLength || !utf8.ValidString(line) {
+				test.Errorf("contact.Test_foldLine[%d]:\n\tinvalid line -> %q", i, line)
+			}
+		}
+	}
+}
@@ -0,0 +1,310 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload // import "github.com/YuriyLisovskiy/qrcode/payload"
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+// Resolver used if a Digital Link has no domain.
+const defaultDigitalLinkDomain = "https://id.gs1.org"
+
+// Error correction level recommended by GS1 for QR Codes on trade items.
+const digitalLinkEcc = qr.EccMedium
+
+// Maximum length of variable-length values (CPV, batch and serial number).
+const maxDigitalLinkValue = 20
+
+// Characters of the GS1 AI encodable character set 82, besides letters and digits.
+const gs1Specials = "!\"%&'()*+,-./:;<=>?_"
+
+// URL-safe base64 alphabet used by compressed Digital Links.
+const digitalLinkAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// Encoding indicators of variable-length values in compressed Digital Links.
+const (
+	dlEncodingNumeric = iota
+	dlEncodingLowerHex
+	dlEncodingUpperHex
+	dlEncodingBase64
+	dlEncodingASCII
+)
+
+// Bits of the length indicator of variable-length values, enough for 20 characters.
+const dlLengthBits = 5
+
+// Describes a GS1 Digital Link of a trade item, e.g.:
+//
+//	HTTPS://ID.GS1.ORG/01/09506000134352/10/ABC123/21/12345?17=251231
+//
+// The path holds the GTIN (AI 01) followed by its qualifiers in the order
+// mandated by the standard: consumer product variant (AI 22), batch or lot
+// (AI 10) and serial number (AI 21). The expiry date (AI 17) is a query
+// attribute. The scheme and domain are written in uppercase so that they are
+// encoded in the alphanumeric mode.
+type DigitalLink struct {
+	// Resolver the link points to, e.g. "https://example.com", "https://id.gs1.org" if empty.
+	Domain string
+
+	// GTIN-8, GTIN-12, GTIN-13 or GTIN-14 with a valid check digit. It is written with
+	// leading zeros as 14 digits.
+	GTIN string
+
+	// Optional consumer product variant, batch or lot number and serial number,
+	// at most 20 characters of the GS1 AI encodable character set 82 each.
+	CPV, Batch, Serial string
+
+	// Optional expiry date as YYMMDD. The day may be "00" for the end of the month.
+	Expiry string
+
+	// Allows writing the values as one base64 string after the domain instead of
+	// the path and the query, if it gives a smaller symbol. The uncompressed form is
+	// kept otherwise, since it stays readable by resolvers without decompression.
+	Compressed bool
+}
+
+// Describes an application identifier (AI) of a Digital Link and its value.
+type digitalLinkAI struct {
+	ai    string
+	value string
+	// Length of fixed-length numeric values, zero for variable-length values.
+	fixed int
+}
+
+// Returns an error if the GTIN has an invalid check digit, or a value is malformed.
+func (d DigitalLink) Validate() error {
+	_, err := d.elements("DigitalLink.Validate")
+	return err
+}
+
+// Returns the Digital Link URI, compressed if allowed and the compressed form
+// takes a smaller symbol, as computed by the encoder.
+func (d DigitalLink) Payload() (string, error) {
+	elements, err := d.elements("DigitalLink.Payload")
+	if err != nil {
+		return "", err
+	}
+	domain := d.Domain
+	if domain == "" {
+		domain = defaultDigitalLinkDomain
+	}
+	domain, err = Link{URL: strings.TrimSuffix(domain, "/")}.Payload()
+	if err != nil {
+		return "", payloadErr("DigitalLink.Payload", fmt.Sprintf("invalid domain '%s'", d.Domain))
+	}
+	var buf bytes.Buffer
+	buf.WriteString(domain)
+	separator := "?"
+	for _, e := range elements {
+		if e.ai == "17" {
+			fmt.Fprintf(&buf, "%s%s=%s", separator, e.ai, e.value)
+			separator = "&"
+		} else {
+			fmt.Fprintf(&buf, "/%s/%s", e.ai, escapeDigitalLinkValue(e.value))
+		}
+	}
+	uncompressed := buf.String()
+	if !d.Compressed {
+		return uncompressed, nil
+	}
+	compressed := domain + "/" + compressDigitalLink(elements)
+	gen := qr.Generator{}
+	compressedVersion, err := gen.MixedTextVersion(compressed, digitalLinkEcc)
+	if err != nil {
+		return "", err
+	}
+	if version, err := gen.MixedTextVersion(uncompressed, digitalLinkEcc); err != nil || compressedVersion < version {
+		return compressed, nil
+	}
+	return uncompressed, nil
+}
+
+// Returns a QR Code of the payload returned by Payload.
+func (d DigitalLink) Encode() (qr.Generator, error) {
+	return encodeMixedPayload(d.Payload, digitalLinkEcc)
+}
+
+// Validates the values and returns them in the order of the Digital Link.
+func (d DigitalLink) elements(method string) ([]digitalLinkAI, error) {
+	if !isGTIN(d.GTIN) {
+		return nil, payloadErr(method, fmt.Sprintf("invalid GTIN '%s'", d.GTIN))
+	}
+	elements := []digitalLinkAI{{ai: "01", value: strings.Repeat("0", 14-len(d.GTIN)) + d.GTIN, fixed: 14}}
+	for _, e := range []struct{ ai, name, value string }{{"22", "CPV", d.CPV}, {"10", "batch", d.Batch}, {"21", "serial", d.Serial}} {
+		if e.value == "" {
+			continue
+		}
+		if len(e.value) > maxDigitalLinkValue || !isGS1Encodable(e.value) {
+			return nil, payloadErr(method, fmt.Sprintf(
+				"%s must be 1 to %d characters of the GS1 character set 82", e.name, maxDigitalLinkValue,
+			))
+		}
+		elements = append(elements, digitalLinkAI{ai: e.ai, value: e.value})
+	}
+	if d.Expiry != "" {
+		if !isGS1Date(d.Expiry) {
+			return nil, payloadErr(method, fmt.Sprintf("invalid expiry date '%s'", d.Expiry))
+		}
+		elements = append(elements, digitalLinkAI{ai: "17", value: d.Expiry, fixed: 6})
+	}
+	return elements, nil
+}
+
+// Returns the values as a URL-safe base64 string, following the binary layout of
+// GS1 Digital Link compression without the optimisation codes for common AI
+// sequences. Every value is written as the digits of its AI in 4-bit nibbles followed by:
+//
+//   - fixed-length numeric values as a binary number of ceil(n * log2(10)) bits;
+//   - variable-length values as a 3-bit encoding indicator, a 5-bit length and
+//     the characters in the most compact of the numeric, lowercase hexadecimal,
+//     uppercase hexadecimal, URL-safe base64 and 7-bit ASCII encodings.
+//
+// The bits are padded with zeros to a multiple of 6.
+func compressDigitalLink(elements []digitalLinkAI) string {
+	var bits bytes.Buffer
+	write := func(value *big.Int, width int) {
+		fmt.Fprintf(&bits, "%0*s", width, fmt.Sprintf("%b", value))
+	}
+	for _, e := range elements {
+		for _, digit := range e.ai {
+			write(big.NewInt(int64(digit-'0')), 4)
+		}
+		if e.fixed != 0 {
+			value, _ := new(big.Int).SetString(e.value, 10)
+			write(value, numericBits(e.fixed))
+			continue
+		}
+		encoding := digitalLinkEncoding(e.value)
+		write(big.NewInt(int64(encoding)), 3)
+		write(big.NewInt(int64(len(e.value))), dlLengthBits)
+		switch encoding {
+		case dlEncodingNumeric:
+			value, _ := new(big.Int).SetString(e.value, 10)
+			write(value, numericBits(len(e.value)))
+		case dlEncodingLowerHex, dlEncodingUpperHex:
+			for _, c := range strings.ToLower(e.value) {
+				write(big.NewInt(int64(strings.IndexRune("0123456789abcdef", c))), 4)
+			}
+		case dlEncodingBase64:
+			for _, c := range e.value {
+				write(big.NewInt(int64(strings.IndexRune(digitalLinkAlphabet, c))), 6)
+			}
+		default:
+			for _, c := range e.value {
+				write(big.NewInt(int64(c)), 7)
+			}
+		}
+	}
+	for bits.Len()%6 != 0 {
+		bits.WriteByte('0')
+	}
+	var result bytes.Buffer
+	s := bits.String()
+	for i := 0; i < len(s); i += 6 {
+		var index int
+		fmt.Sscanf(s[i:i+6], "%b", &index)
+		result.WriteByte(digitalLinkAlphabet[index])
+	}
+	return result.String()
+}
+
+// Returns the most compact encoding of a variable-length value.
+func digitalLinkEncoding(value string) int {
+	only := func(charset string) bool {
+		for _, c := range value {
+			if !strings.ContainsRune(charset, c) {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case only("0123456789"):
+		return dlEncodingNumeric
+	case only("0123456789abcdef"):
+		return dlEncodingLowerHex
+	case only("0123456789ABCDEF"):
+		return dlEncodingUpperHex
+	case only(digitalLinkAlphabet):
+		return dlEncodingBase64
+	default:
+		return dlEncodingASCII
+	}
+}
+
+// Returns the number of bits of the largest number of n decimal digits, ceil(n * log2(10)).
+func numericBits(n int) int {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+	return max.Sub(max, big.NewInt(1)).BitLen()
+}
+
+// Percent-encodes characters of a path value other than letters, digits, "-", ".", "_" and "~".
+func escapeDigitalLinkValue(value string) string {
+	var buf bytes.Buffer
+	for _, c := range []byte(value) {
+		if isDigit(rune(c)) || isUpperLetter(rune(c)) || 'a' <= c && c <= 'z' || strings.IndexByte("-._~", c) != -1 {
+			buf.WriteByte(c)
+		} else {
+			fmt.Fprintf(&buf, "%%%02X", c)
+		}
+	}
+	return buf.String()
+}
+
+// Returns true if s is a GTIN-8, GTIN-12, GTIN-13 or GTIN-14 with a valid check digit.
+func isGTIN(s string) bool {
+	if len(s) != 8 && len(s) != 12 && len(s) != 13 && len(s) != 14 {
+		return false
+	}
+	sum := 0
+	for i := len(s) - 1; i >= 0; i-- {
+		if !isDigit(rune(s[i])) {
+			return false
+		}
+		digit := int(s[i] - '0')
+		// Weights alternate 1 and 3 from the check digit to the left.
+		if (len(s)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+// Returns true if s consists of characters of the GS1 AI encodable character set 82.
+func isGS1Encodable(s string) bool {
+	for _, c := range s {
+		if !isDigit(c) && !isUpperLetter(c) && !('a' <= c && c <= 'z') && !strings.ContainsRune(gs1Specials, c) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Returns true if s is a GS1 date YYMMDD, where the day may be "00".
+func isGS1Date(s string) bool {
+	if len(s) != 6 || !isNumericString(s) {
+		return false
+	}
+	month := int(s[2]-'0')*10 + int(s[3]-'0')
+	day := int(s[4]-'0')*10 + int(s[5]-'0')
+	days := [13]int{0, 31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+	return 1 <= month && month <= 12 && day <= days[month]
+}
+
+// Returns true if s is a non-empty string of decimal digits.
+func isNumericString(s string) bool {
+	for _, c := range s {
+		if !isDigit(c) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,219 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+// Decodes the values of a compressed Digital Link, see compressDigitalLink.
+func decompressDigitalLink(s string) (map[string]string, error) {
+	var bits bitReader
+	for _, c := range s {
+		index := strings.IndexRune(digitalLinkAlphabet, c)
+		if index == -1 {
+			return nil, fmt.Errorf("invalid character %q", c)
+		}
+		bits.s += fmt.Sprintf("%06b", index)
+	}
+	fixed := map[string]int{"01": 14, "17": 6}
+	result := map[string]string{}
+	for len(bits.s) >= 8 {
+		ai := fmt.Sprintf("%d%d", bits.read(4).Int64(), bits.read(4).Int64())
+		if n, ok := fixed[ai]; ok {
+			result[ai] = fmt.Sprintf("%0*s", n, bits.read(numericBits(n)).String())
+			continue
+		}
+		encoding := int(bits.read(3).Int64())
+		length := int(bits.read(dlLengthBits).Int64())
+		var value string
+		for i := 0; i < length && encoding != dlEncodingNumeric; i++ {
+			switch encoding {
+			case dlEncodingLowerHex:
+				value += string("0123456789abcdef"[bits.read(4).Int64()])
+			case dlEncodingUpperHex:
+				value += string("0123456789ABCDEF"[bits.read(4).Int64()])
+			case dlEncodingBase64:
+				value += string(digitalLinkAlphabet[bits.read(6).Int64()])
+			default:
+				value += string(rune(bits.read(7).Int64()))
+			}
+		}
+		if encoding == dlEncodingNumeric {
+			value = fmt.Sprintf("%0*s", length, bits.read(numericBits(length)).String())
+		}
+		result[ai] = value
+	}
+	if strings.Trim(bits.s, "0") != "" {
+		return nil, fmt.Errorf("non-zero padding %s", bits.s)
+	}
+	return result, nil
+}
+
+// Reads numbers from a string of bits.
+type bitReader struct {
+	s string
+}
+
+func (b *bitReader) read(n int) *big.Int {
+	value, _ := new(big.Int).SetString("0"+b.s[:n], 2)
+	b.s = b.s[n:]
+	return value
+}
+
+var testDigitalLink = DigitalLink{GTIN: "9506000134352", Batch: "ABC123", Serial: "12345", Expiry: "251231"}
+
+var DigitalLinkPayload_TestData = []struct {
+	link     DigitalLink
+	expected string
+}{
+	{link: testDigitalLink, expected: "HTTPS://ID.GS1.ORG/01/09506000134352/10/ABC123/21/12345?17=251231"},
+	{
+		link:     DigitalLink{Domain: "https://example.com/dl/", GTIN: "95070001", CPV: "2A", Serial: "A/B%c"},
+		expected: "HTTPS://EXAMPLE.COM/dl/01/00000095070001/22/2A/21/A%2FB%25c",
+	},
+	{link: DigitalLink{GTIN: "09506000134352", Expiry: "261100"}, expected: "HTTPS://ID.GS1.ORG/01/09506000134352?17=261100"},
+}
+
+func Test_DigitalLinkPayload(test *testing.T) {
+	for i, data := range DigitalLinkPayload_TestData {
+		if actual, err := data.link.Payload(); err != nil || actual != data.expected {
+			test.Errorf(
+				"digital_link.Test_DigitalLinkPayload[%d]:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+}
+
+var DigitalLinkCompressed_TestData = []struct {
+	link     DigitalLink
+	expected map[string]string
+}{
+	{
+		link:     testDigitalLink,
+		expected: map[string]string{"01": "09506000134352", "10": "ABC123", "21": "12345", "17": "251231"},
+	},
+	{
+		link:     DigitalLink{GTIN: "95070001", CPV: "ab12", Batch: "0042", Serial: "Lot_7-x"},
+		expected: map[string]string{"01": "00000095070001", "22": "ab12", "10": "0042", "21": "Lot_7-x"},
+	},
+	{
+		link:     DigitalLink{GTIN: "09506000134352", Batch: "FF00", Serial: "a.b/c"},
+		expected: map[string]string{"01": "09506000134352", "10": "FF00", "21": "a.b/c"},
+	},
+}
+
+func Test_DigitalLinkCompressed(test *testing.T) {
+	for i, data := range DigitalLinkCompressed_TestData {
+		elements, err := data.link.elements("DigitalLink.Payload")
+		if err != nil {
+			test.Errorf("digital_link.Test_DigitalLinkCompressed[%d]:\n\tunexpected error -> %s", i, err)
+			continue
+		}
+		actual, err := decompressDigitalLink(compressDigitalLink(elements))
+		if err != nil || !reflect.DeepEqual(actual, data.expected) {
+			test.Errorf(
+				"digital_link.Test_DigitalLinkCompressed[%d]:\n\tactual -> %v (err: %v)\n is not equal to\n\texpected -> %v",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+	// Hexadecimal values are packed into 4 bits per character.
+	link := DigitalLink{GTIN: "09506000134352", CPV: "aabbccddeeff00112233", Batch: "abcdefabcdefabcdef12", Compressed: true}
+	payload, err := link.Payload()
+	if err != nil || strings.Contains(payload, "/01/") {
+		test.Errorf("digital_link.Test_DigitalLinkCompressed:\n\tuncompressed form is chosen -> %q (err: %v)", payload, err)
+	}
+	gen, err := link.Encode()
+	if err != nil {
+		test.Fatalf("digital_link.Test_DigitalLinkCompressed:\n\tunexpected error -> %s", err)
+	}
+	link.Compressed = false
+	uncompressed, _ := link.Encode()
+	if len(gen.GetModules()) >= len(uncompressed.GetModules()) {
+		test.Errorf(
+			"digital_link.Test_DigitalLinkCompressed:\n\tactual size -> %d\n is not less than\n\tuncompressed size -> %d",
+			len(gen.GetModules()), len(uncompressed.GetModules()),
+		)
+	}
+	// The uncompressed form is kept if both take the same symbol.
+	link = DigitalLink{GTIN: "09506000134352", Batch: "lot-a1b2c3d4e5f6g7h8", Serial: "sn.x9y8z7w6v5u4t3s2"}
+	expected, _ := link.Payload()
+	link.Compressed = true
+	if actual, err := link.Payload(); err != nil || actual != expected {
+		test.Errorf("digital_link.Test_DigitalLinkCompressed:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q", actual, err, expected)
+	}
+	expectedGen := qr.Generator{}
+	version, _ := expectedGen.MixedTextVersion(expected, digitalLinkEcc)
+	if gen, err := link.Encode(); err != nil || len(gen.GetModules()) != version*4+17 {
+		test.Errorf("digital_link.Test_DigitalLinkCompressed:\n\tunexpected symbol (err: %v)", err)
+	}
+}
+
+var DigitalLinkValidate_TestData = []struct {
+	link     DigitalLink
+	expected string
+}{
+	{link: DigitalLink{GTIN: "9506000134353"}, expected: "invalid GTIN '9506000134353'"},
+	{link: DigitalLink{GTIN: "950600013435"}, expected: "invalid GTIN '950600013435'"},
+	{link: DigitalLink{GTIN: "95060001343A2"}, expected: "invalid GTIN '95060001343A2'"},
+	{
+		link:     DigitalLink{GTIN: "9506000134352", Batch: strings.Repeat("1", 21)},
+		expected: "batch must be 1 to 20 characters of the GS1 character set 82",
+	},
+	{
+		link:     DigitalLink{GTIN: "9506000134352", Serial: "#1"},
+		expected: "serial must be 1 to 20 characters of the GS1 character set 82",
+	},
+	{link: DigitalLink{GTIN: "9506000134352", Expiry: "251301"}, expected: "invalid expiry date '251301'"},
+	{link: DigitalLink{GTIN: "9506000134352", Expiry: "250431"}, expected: "invalid expiry date '250431'"},
+}
+
+func Test_DigitalLinkValidate(test *testing.T) {
+	for i, data := range DigitalLinkValidate_TestData {
+		expected := payloadErr("DigitalLink.Validate", data.expected)
+		if actual := data.link.Validate(); actual == nil || actual.Error() != expected.Error() {
+			test.Errorf(
+				"digital_link.Test_DigitalLinkValidate[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, expected,
+			)
+		}
+	}
+	link := DigitalLink{Domain: "id.gs1.org", GTIN: "9506000134352"}
+	if _, err := link.Payload(); err == nil {
+		test.Errorf("digital_link.Test_DigitalLinkValidate:\n\tdomain without a scheme is accepted")
+	}
+}
+
+var isGTIN_TestData = []struct {
+	gtin     string
+	expected bool
+}{
+	{gtin: "95070001", expected: true},
+	{gtin: "614141000036", expected: true},
+	{gtin: "9506000134352", expected: true},
+	{gtin: "09506000134352", expected: true},
+	{gtin: "10614141000033", expected: true},
+	{gtin: "10614141000034", expected: false},
+	{gtin: "950700011", expected: false},
+}
+
+func Test_isGTIN(test *testing.T) {
+	for i, data := range isGTIN_TestData {
+		if actual := isGTIN(data.gtin); actual != data.expected {
+			test.Errorf(
+				"digital_link.Test_isGTIN[%d]:\n\tactual -> %t\n is not equal to\n\texpected -> %t",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload // import "github.com/YuriyLisovskiy/qrcode/payload"
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+// Maximum length of an EMV data object value in characters.
+const maxEmvValueLength = 99
+
+// ID of the CRC data object, which terminates every payload.
+const emvCrcID = "63"
+
+// IDs of data objects every merchant-presented payload has to contain,
+// in addition to the payload format indicator and merchant account information.
+var emvMandatoryIDs = []string{
+	"52", // Merchant category code
+	"53", // Transaction currency
+	"58", // Country code
+	"59", // Merchant name
+	"60", // Merchant city
+}
+
+// Describes a data object of an EMV merchant-presented payload. Objects of
+// templates, e.g. merchant account information (IDs 26 to 51) or additional
+// data (ID 62), contain nested objects instead of a value.
+type EmvObject struct {
+	// Two-digit identifier, e.g. "59" for the merchant name.
+	ID string
+
+	// Value of a primitive object, at most 99 characters.
+	Value string
+
+	// Nested objects of a template, the value is built of them.
+	Template []EmvObject
+}
+
+// Describes a merchant-presented payload of the EMV QR Code Specification for
+// Payment Systems (EMV QRCPS), which PIX, PayNow, DuitNow and other national
+// schemes are built on. Length fields and the CRC object (ID 63) are added by
+// Payload and must not be given.
+type EmvPayload struct {
+	Objects []EmvObject
+}
+
+// Returns the first top-level object with the given ID.
+func (p EmvPayload) Get(id string) (EmvObject, bool) {
+	for _, object := range p.Objects {
+		if object.ID == id {
+			return object, true
+		}
+	}
+	return EmvObject{}, false
+}
+
+// Returns an error if an object is malformed or a mandatory object is missing.
+func (p EmvPayload) Validate() error {
+	_, err := p.tlv("EmvPayload.Validate")
+	return err
+}
+
+// Returns the payload in the TLV format, terminated by the CRC object.
+func (p EmvPayload) Payload() (string, error) {
+	text, err := p.tlv("EmvPayload.Payload")
+	if err != nil {
+		return "", err
+	}
+	text += emvCrcID + "04"
+	return text + fmt.Sprintf("%04X", crc16(text)), nil
+}
+
+// Returns a QR Code of the payload. Payment codes are often shown on screens
+// and printed on stickers, so at least the medium error correction level is used.
+func (p EmvPayload) Encode() (qr.Generator, error) {
+	return encodePayload(p.Payload, qr.EccMedium)
+}
+
+// Parses a merchant-presented payload, verifying its CRC and mandatory objects.
+// Values of template objects are parsed into nested objects. The CRC object is
+// not included into the result.
+func ParseEmvPayload(s string) (EmvPayload, error) {
+	const method = "ParseEmvPayload"
+	if len(s) < 8 || s[len(s)-8:len(s)-4] != emvCrcID+"04" {
+		return EmvPayload{}, payloadErr(method, "payload does not end with a CRC object")
+	}
+	crc, err := strconv.ParseUint(s[len(s)-4:], 16, 16)
+	if err != nil {
+		return EmvPayload{}, payloadErr(method, fmt.Sprintf("malformed CRC '%s'", s[len(s)-4:]))
+	}
+	if actual := crc16(s[:len(s)-4]); uint16(crc) != actual {
+		return EmvPayload{}, payloadErr(method, fmt.Sprintf("CRC mismatch: %04X, computed %04X", crc, actual))
+	}
+	objects, err := parseEmvObjects(method, s[:len(s)-8], true)
+	if err != nil {
+		return EmvPayload{}, err
+	}
+	p := EmvPayload{Objects: objects}
+	if err := p.Validate(); err != nil {
+		return EmvPayload{}, err
+	}
+	return p, nil
+}
+
+// Validates the payload and returns its objects in the TLV format without the CRC object.
+func (p EmvPayload) tlv(method string) (string, error) {
+	if len(p.Objects) == 0 || p.Objects[0].ID != "00" || p.Objects[0].Value != "01" {
+		return "", payloadErr(method, "payload format indicator (ID 00) must be the first object and equal to \"01\"")
+	}
+	seen := map[string]bool{}
+	accounts := 0
+	for _, object := range p.Objects {
+		if object.ID == emvCrcID {
+			return "", payloadErr(method, "CRC object (ID 63) is added automatically")
+		}
+		if seen[object.ID] {
+			return "", payloadErr(method, fmt.Sprintf("object %s is repeated", object.ID))
+		}
+		seen[object.ID] = true
+		if "02" <= object.ID && object.ID <= "51" {
+			accounts++
+		}
+	}
+	if accounts == 0 {
+		return "", payloadErr(method, "merchant account information (IDs 02 to 51) is missing")
+	}
+	for _, id := range emvMandatoryIDs {
+		if !seen[id] {
+			return "", payloadErr(method, fmt.Sprintf("mandatory object %s is missing", id))
+		}
+	}
+	return encodeEmvObjects(method, p.Objects)
+}
+
+// Returns the given objects in the TLV format.
+func encodeEmvObjects(method string, objects []EmvObject) (string, error) {
+	var buf bytes.Buffer
+	for _, object := range objects {
+		if len(object.ID) != 2 || !isDigit(rune(object.ID[0])) || !isDigit(rune(object.ID[1])) {
+			return "", payloadErr(method, fmt.Sprintf("invalid object ID '%s'", object.ID))
+		}
+		value := object.Value
+		if len(object.Template) > 0 {
+			if value != "" {
+				return "", payloadErr(method, fmt.Sprintf("object %s has both a value and a template", object.ID))
+			}
+			var err error
+			if value, err = encodeEmvObjects(method, object.Template); err != nil {
+				return "", err
+			}
+		}
+		length := utf8.RuneCountInString(value)
+		if length == 0 {
+			return "", payloadErr(method, fmt.Sprintf("object %s is empty", object.ID))
+		}
+		if length > maxEmvValueLength {
+			return "", payloadErr(method, fmt.Sprintf(
+				"object %s is longer than %d characters", object.ID, maxEmvValueLength,
+			))
+		}
+		fmt.Fprintf(&buf, "%s%02d%s", object.ID, length, value)
+	}
+	return buf.String(), nil
+}
+
+// Parses objects in the TLV format. Template objects are parsed recursively if top is true.
+func parseEmvObjects(method, s string, top bool) ([]EmvObject, error) {
+	var objects []EmvObject
+	for s != "" {
+		if len(s) < 4 {
+			return nil, payloadErr(method, fmt.Sprintf("truncated object '%s'", s))
+		}
+		id := s[:2]
+		length, err := strconv.Atoi(s[2:4])
+		if err != nil || length == 0 {
+			return nil, payloadErr(method, fmt.Sprintf("invalid length of object %s", id))
+		}
+		s = s[4:]
+		// Lengths are measured in characters, not bytes.
+		end := 0
+		for i := 0; i < length; i++ {
+			if end >= len(s) {
+				return nil, payloadErr(method, fmt.Sprintf("object %s is truncated", id))
+			}
+			_, size := utf8.DecodeRuneInString(s[end:])
+			end += size
+		}
+		object := EmvObject{ID: id, Value: s[:end]}
+		if top && isEmvTemplate(id) {
+			if object.Template, err = parseEmvObjects(method, object.Value, false); err != nil {
+				return nil, err
+			}
+			object.Value = ""
+		}
+		objects = append(objects, object)
+		s = s[end:]
+	}
+	return objects, nil
+}
+
+// Returns true if top-level objects with the given ID are templates: merchant
+// account information (26 to 51), additional data (62), merchant information
+// in an alternate language (64) and unreserved templates (80 to 99).
+func isEmvTemplate(id string) bool {
+	return "26" <= id && id <= "51" || id == "62" || id == "64" || "80" <= id && id <= "99"
+}
+
+// Returns the CRC-16/CCITT-FALSE checksum of s: polynomial 0x1021, initial value 0xFFFF.
+func crc16(s string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,173 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Example of the EMV QR Code Specification for Payment Systems.
+const testEmvSpecPayload = "00020101021229300012D156000000000510A93FO3230Q31280012D15600000001030812345678" +
+	"520441115802CN5914BEST TRANSPORT6007BEIJING64200002ZH0104最佳运输0202北京540523.7253031565502016233030412340603***" +
+	"0708A60086670902ME91320016A0112233449988770708123456786304A13A"
+
+var testEmvPayload = EmvPayload{Objects: []EmvObject{
+	{ID: "00", Value: "01"},
+	{ID: "26", Template: []EmvObject{
+		{ID: "00", Value: "br.gov.bcb.pix"},
+		{ID: "01", Value: "123e4567-e12b-12d1-a456-426655440000"},
+	}},
+	{ID: "52", Value: "0000"},
+	{ID: "53", Value: "986"},
+	{ID: "54", Value: "10.00"},
+	{ID: "58", Value: "BR"},
+	{ID: "59", Value: "Fulano de Tal"},
+	{ID: "60", Value: "BRASILIA"},
+	{ID: "62", Template: []EmvObject{{ID: "05", Value: "***"}}},
+}}
+
+func Test_EmvPayload(test *testing.T) {
+	expected := "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-426655440000" +
+		"5204000053039865405" + "10.00" + "5802BR5913Fulano de Tal6008BRASILIA62070503***6304"
+	actual, err := testEmvPayload.Payload()
+	if err != nil || !strings.HasPrefix(actual, expected) || len(actual) != len(expected)+4 {
+		test.Fatalf("emv.Test_EmvPayload:\n\tactual -> %q (err: %v)\n does not start with\n\texpected -> %q", actual, err, expected)
+	}
+	parsed, err := ParseEmvPayload(actual)
+	if err != nil || !reflect.DeepEqual(parsed, testEmvPayload) {
+		test.Errorf("emv.Test_EmvPayload:\n\tactual parsed -> %v (err: %v)\n is not equal to\n\texpected -> %v", parsed, err, testEmvPayload)
+	}
+	if _, err := testEmvPayload.Encode(); err != nil {
+		test.Errorf("emv.Test_EmvPayload:\n\tunexpected error -> %s", err)
+	}
+}
+
+func Test_ParseEmvPayload(test *testing.T) {
+	parsed, err := ParseEmvPayload(testEmvSpecPayload)
+	if err != nil {
+		test.Fatalf("emv.Test_ParseEmvPayload:\n\tunexpected error -> %s", err)
+	}
+	language, ok := parsed.Get("64")
+	expected := []EmvObject{{ID: "00", Value: "ZH"}, {ID: "01", Value: "最佳运输"}, {ID: "02", Value: "北京"}}
+	if !ok || !reflect.DeepEqual(language.Template, expected) {
+		test.Errorf("emv.Test_ParseEmvPayload:\n\tactual template -> %v\n is not equal to\n\texpected -> %v", language.Template, expected)
+	}
+	if actual, err := parsed.Payload(); err != nil || actual != testEmvSpecPayload {
+		test.Errorf("emv.Test_ParseEmvPayload:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q", actual, err, testEmvSpecPayload)
+	}
+}
+
+var ParseEmvPayloadErr_TestData = []struct {
+	payload  string
+	expected string
+}{
+	{payload: "000201", expected: "payload does not end with a CRC object"},
+	{payload: testEmvSpecPayload[:len(testEmvSpecPayload)-1] + "B", expected: "CRC mismatch: A13B, computed A13A"},
+	{payload: "0002016304ZZZZ", expected: "malformed CRC 'ZZZZ'"},
+}
+
+func Test_ParseEmvPayloadErr(test *testing.T) {
+	for i, data := range ParseEmvPayloadErr_TestData {
+		expected := payloadErr("ParseEmvPayload", data.expected)
+		if _, actual := ParseEmvPayload(data.payload); actual == nil || actual.Error() != expected.Error() {
+			test.Errorf(
+				"emv.Test_ParseEmvPayloadErr[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, expected,
+			)
+		}
+	}
+	// Objects are parsed after the CRC is verified.
+	truncated := "0002010104" + emvCrcID + "04"
+	truncated += fmt.Sprintf("%04X", crc16(truncated))
+	expected := payloadErr("ParseEmvPayload", "object 01 is truncated")
+	if _, actual := ParseEmvPayload(truncated); actual == nil || actual.Error() != expected.Error() {
+		test.Errorf(
+			"emv.Test_ParseEmvPayloadErr:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+			actual, expected,
+		)
+	}
+}
+
+var EmvPayloadValidate_TestData = []struct {
+	modify   func(objects []EmvObject) []EmvObject
+	expected string
+}{
+	{
+		modify:   func(objects []EmvObject) []EmvObject { return objects[1:] },
+		expected: "payload format indicator (ID 00) must be the first object and equal to \"01\"",
+	},
+	{
+		modify:   func(objects []EmvObject) []EmvObject { return append(objects, EmvObject{ID: "63", Value: "ABCD"}) },
+		expected: "CRC object (ID 63) is added automatically",
+	},
+	{
+		modify:   func(objects []EmvObject) []EmvObject { return append(objects, EmvObject{ID: "59", Value: "Other"}) },
+		expected: "object 59 is repeated",
+	},
+	{
+		modify:   func(objects []EmvObject) []EmvObject { return append(objects[:1:1], objects[2:]...) },
+		expected: "merchant account information (IDs 02 to 51) is missing",
+	},
+	{
+		modify:   func(objects []EmvObject) []EmvObject { return append(objects[:7:7], objects[8:]...) },
+		expected: "mandatory object 60 is missing",
+	},
+	{
+		modify:   func(objects []EmvObject) []EmvObject { return append(objects, EmvObject{ID: "7", Value: "x"}) },
+		expected: "invalid object ID '7'",
+	},
+	{
+		modify:   func(objects []EmvObject) []EmvObject { return append(objects, EmvObject{ID: "55", Value: ""}) },
+		expected: "object 55 is empty",
+	},
+	{
+		modify: func(objects []EmvObject) []EmvObject {
+			return append(objects, EmvObject{ID: "80", Template: []EmvObject{{ID: "00", Value: strings.Repeat("x", 100)}}})
+		},
+		expected: "object 00 is longer than 99 characters",
+	},
+	{
+		modify: func(objects []EmvObject) []EmvObject {
+			return append(objects, EmvObject{ID: "80", Value: "x", Template: []EmvObject{{ID: "00", Value: "y"}}})
+		},
+		expected: "object 80 has both a value and a template",
+	},
+}
+
+func Test_EmvPayloadValidate(test *testing.T) {
+	for i, data := range EmvPayloadValidate_TestData {
+		objects := append([]EmvObject{}, testEmvPayload.Objects...)
+		payload := EmvPayload{Objects: data.modify(objects)}
+		expected := payloadErr("EmvPayload.Validate", data.expected)
+		if actual := payload.Validate(); actual == nil || actual.Error() != expected.Error() {
+			test.Errorf(
+				"emv.Test_EmvPayloadValidate[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, expected,
+			)
+		}
+	}
+}
+
+var crc16_TestData = []struct {
+	data     string
+	expected uint16
+}{
+	{data: "", expected: 0xFFFF},
+	{data: "123456789", expected: 0x29B1},
+}
+
+func Test_crc16(test *testing.T) {
+	for i, data := range crc16_TestData {
+		if actual := crc16(data.data); actual != data.expected {
+			test.Errorf(
+				"emv.Test_crc16[%d]:\n\tactual -> %04X\n is not equal to\n\texpected -> %04X",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload // import "github.com/YuriyLisovskiy/qrcode/payload"
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Compose payload error message
+func payloadErr(method, msg string) error {
+	return errors.New(fmt.Sprintf("package payload: %s: %s", method, msg))
+}
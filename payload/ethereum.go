@@ -0,0 +1,144 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload // import "github.com/YuriyLisovskiy/qrcode/payload"
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+// Describes a parameter of an Ethereum payment request, e.g. the recipient
+// and the amount of a token transfer: {"address", "0x..."}, {"uint256", "1000000"}.
+type EthereumParameter struct {
+	Name, Value string
+}
+
+// Describes an Ethereum payment request, encoded as an EIP-681 URI:
+//
+//	ethereum:0xfb6916095ca1df60bB79Ce92cE3Ea74c37c5d359@1?value=2014000000000000000
+//
+// Addresses are validated and written with the EIP-55 checksum, so they are
+// mixed case and encoded in the byte mode.
+type EthereumPayment struct {
+	// Address of the recipient or, for function calls, of the contract.
+	Address string
+
+	// Chain ID of the network, e.g. 1 for the main network. Zero leaves it to the wallet.
+	ChainID uint64
+
+	// Optional function called on the contract, e.g. "transfer".
+	Function string
+
+	// Optional amount of ether in wei.
+	Value *big.Int
+
+	// Parameters of the function. Values of "address" parameters are validated
+	// and written with the checksum as well.
+	Parameters []EthereumParameter
+}
+
+// Returns an error if an address has an invalid checksum, or the function name
+// or the value are malformed.
+func (e EthereumPayment) Validate() error {
+	_, err := e.payload("EthereumPayment.Validate")
+	return err
+}
+
+// Returns the EIP-681 URI of the payment.
+func (e EthereumPayment) Payload() (string, error) {
+	return e.payload("EthereumPayment.Payload")
+}
+
+// Returns a QR Code of the payment URI. Payment requests are usually shown
+// on screens, so the medium error correction level is enough.
+func (e EthereumPayment) Encode() (qr.Generator, error) {
+	return encodePayload(e.Payload, qr.EccMedium)
+}
+
+// Validates the payment and builds its URI, reporting errors on behalf of method.
+func (e EthereumPayment) payload(method string) (string, error) {
+	address, err := checksumEthereumAddress(e.Address)
+	if err != nil {
+		return "", payloadErr(method, err.Error())
+	}
+	var buf bytes.Buffer
+	buf.WriteString("ethereum:" + address)
+	if e.ChainID != 0 {
+		fmt.Fprintf(&buf, "@%d", e.ChainID)
+	}
+	if e.Function != "" {
+		if !isIdentifier(e.Function) {
+			return "", payloadErr(method, fmt.Sprintf("invalid function name '%s'", e.Function))
+		}
+		buf.WriteString("/" + e.Function)
+	}
+	var params []string
+	if e.Value != nil {
+		if e.Value.Sign() < 0 {
+			return "", payloadErr(method, "value must not be negative")
+		}
+		params = append(params, "value="+e.Value.String())
+	}
+	for _, param := range e.Parameters {
+		if !isIdentifier(param.Name) {
+			return "", payloadErr(method, fmt.Sprintf("invalid parameter name '%s'", param.Name))
+		}
+		value := param.Value
+		if param.Name == "address" {
+			if value, err = checksumEthereumAddress(value); err != nil {
+				return "", payloadErr(method, err.Error())
+			}
+		}
+		params = append(params, param.Name+"="+queryEscape(value))
+	}
+	if len(params) > 0 {
+		buf.WriteString("?" + strings.Join(params, "&"))
+	}
+	return buf.String(), nil
+}
+
+// Returns the address with the EIP-55 checksum: hexadecimal letters are
+// uppercase where the corresponding nibble of the Keccak-256 hash of the
+// lowercase address is at least 8. Mixed case addresses have to carry
+// a valid checksum already, single case ones are accepted as is.
+func checksumEthereumAddress(address string) (string, error) {
+	if len(address) != 42 || !strings.HasPrefix(address, "0x") || !isHex(address[2:]) {
+		return "", fmt.Errorf("invalid address '%s'", address)
+	}
+	hex := address[2:]
+	lower := strings.ToLower(hex)
+	hash := keccak256([]byte(lower))
+	checksummed := []byte(lower)
+	for i, c := range checksummed {
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0F
+		}
+		if c >= 'a' && nibble >= 8 {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+	if hex != lower && hex != strings.ToUpper(hex) && hex != string(checksummed) {
+		return "", fmt.Errorf("invalid checksum of address '%s'", address)
+	}
+	return "0x" + string(checksummed), nil
+}
+
+// Returns true if s is a Solidity identifier or type name, e.g. "transfer" or "uint256".
+func isIdentifier(s string) bool {
+	if s == "" || isDigit(rune(s[0])) {
+		return false
+	}
+	for _, c := range s {
+		if !isDigit(c) && !('a' <= c && c <= 'z') && !isUpperLetter(c) && c != '_' && c != '$' {
+			return false
+		}
+	}
+	return true
+}
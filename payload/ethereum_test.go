@@ -0,0 +1,113 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+var EthereumPaymentPayload_TestData = []struct {
+	payment  EthereumPayment
+	expected string
+}{
+	{
+		payment:  EthereumPayment{Address: "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", ChainID: 1, Value: big.NewInt(2014000000000000000)},
+		expected: "ethereum:0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359@1?value=2014000000000000000",
+	},
+	{
+		payment: EthereumPayment{
+			Address:  "0x89205A3A3B2A69DE6DBF7F01ED13B2108B2C43E7",
+			Function: "transfer",
+			Parameters: []EthereumParameter{
+				{Name: "address", Value: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+				{Name: "uint256", Value: "1000000"},
+			},
+		},
+		expected: "ethereum:0x89205A3A3b2A69De6Dbf7f01ED13B2108B2c43e7/transfer" +
+			"?address=0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed&uint256=1000000",
+	},
+	{
+		payment:  EthereumPayment{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		expected: "ethereum:0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	},
+}
+
+func Test_EthereumPaymentPayload(test *testing.T) {
+	for i, data := range EthereumPaymentPayload_TestData {
+		if actual, err := data.payment.Payload(); err != nil || actual != data.expected {
+			test.Errorf(
+				"ethereum.Test_EthereumPaymentPayload[%d]:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+	if _, err := EthereumPaymentPayload_TestData[0].payment.Encode(); err != nil {
+		test.Errorf("ethereum.Test_EthereumPaymentPayload:\n\tunexpected error -> %s", err)
+	}
+}
+
+var EthereumPaymentValidate_TestData = []struct {
+	payment  EthereumPayment
+	expected string
+}{
+	{payment: EthereumPayment{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe"}, expected: "invalid address '0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe'"},
+	{
+		payment:  EthereumPayment{Address: "0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		expected: "invalid checksum of address '0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed'",
+	},
+	{
+		payment:  EthereumPayment{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", Function: "1st"},
+		expected: "invalid function name '1st'",
+	},
+	{
+		payment:  EthereumPayment{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", Value: big.NewInt(-1)},
+		expected: "value must not be negative",
+	},
+	{
+		payment: EthereumPayment{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			Parameters: []EthereumParameter{{Name: "address", Value: "vitalik.eth"}}},
+		expected: "invalid address 'vitalik.eth'",
+	},
+	{
+		payment: EthereumPayment{Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			Parameters: []EthereumParameter{{Name: "gas limit", Value: "1"}}},
+		expected: "invalid parameter name 'gas limit'",
+	},
+}
+
+func Test_EthereumPaymentValidate(test *testing.T) {
+	for i, data := range EthereumPaymentValidate_TestData {
+		expected := payloadErr("EthereumPayment.Validate", data.expected)
+		if actual := data.payment.Validate(); actual == nil || actual.Error() != expected.Error() {
+			test.Errorf(
+				"ethereum.Test_EthereumPaymentValidate[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, expected,
+			)
+		}
+	}
+}
+
+// Examples of EIP-55.
+var checksumEthereumAddress_TestData = []string{
+	"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+	"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+	"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+}
+
+func Test_checksumEthereumAddress(test *testing.T) {
+	for i, expected := range checksumEthereumAddress_TestData {
+		for _, address := range []string{expected, strings.ToLower(expected), "0x" + strings.ToUpper(expected[2:])} {
+			if actual, err := checksumEthereumAddress(address); err != nil || actual != expected {
+				test.Errorf(
+					"ethereum.Test_checksumEthereumAddress[%d]:\n\tactual -> %s (err: %v)\n is not equal to\n\texpected -> %s",
+					i, actual, err, expected,
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload // import "github.com/YuriyLisovskiy/qrcode/payload"
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+// Layouts of iCalendar DATE and DATE-TIME values.
+const (
+	icalDate     = "20060102"
+	icalDateTime = "20060102T150405"
+)
+
+// Describes a calendar event, encoded as a single iCalendar VEVENT (RFC 5545)
+// which phones offer to add to a calendar.
+type Event struct {
+	// Globally unique identifier of the event, e.g. "talk-42@conf.example.com".
+	UID string
+
+	// Start and optional end of the event. Times in UTC or in the local time zone
+	// of the process are written in UTC, times in any other location are written
+	// with the name of the location as the time zone identifier, e.g. "Europe/Kyiv".
+	Start, End time.Time
+
+	// Writes only dates of the start and end. The end date is the last day
+	// of the event, DTEND is written as the following day since it is exclusive.
+	AllDay bool
+
+	// Time the event was created, written as DTSTAMP in UTC. The current time is used if zero.
+	Stamp time.Time
+
+	Summary, Location, Description string
+}
+
+// Returns an error if the event has no identifier or start, or ends before it starts.
+func (e Event) Validate() error {
+	if e.UID == "" {
+		return payloadErr("Event.Validate", "event has no UID")
+	}
+	if e.Start.IsZero() {
+		return payloadErr("Event.Validate", "event has no start")
+	}
+	if !e.End.IsZero() && e.End.Before(e.Start) {
+		return payloadErr("Event.Validate", "event ends before it starts")
+	}
+	return nil
+}
+
+// Returns the event as a minimal VEVENT block: empty properties are left out,
+// as well as the end if it is equal to the start or an all-day event ends
+// on the day it starts, and no calendar wrapper is written around the event.
+func (e Event) Payload() (string, error) {
+	if err := e.Validate(); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	line := func(s string) {
+		buf.WriteString(foldLine(s))
+	}
+	line("BEGIN:VEVENT")
+	line("UID:" + escapeText(e.UID))
+	stamp := e.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	line("DTSTAMP:" + stamp.UTC().Format(icalDateTime) + "Z")
+	line("DTSTART" + e.icalTime(e.Start))
+	switch {
+	case e.End.IsZero():
+	case e.AllDay:
+		if end := e.End.Format(icalDate); end > e.Start.Format(icalDate) {
+			line("DTEND" + e.icalTime(e.End.AddDate(0, 0, 1)))
+		}
+	case !e.End.Equal(e.Start):
+		line("DTEND" + e.icalTime(e.End))
+	}
+	if e.Summary != "" {
+		line("SUMMARY:" + escapeText(e.Summary))
+	}
+	if e.Location != "" {
+		line("LOCATION:" + escapeText(e.Location))
+	}
+	if e.Description != "" {
+		line("DESCRIPTION:" + escapeText(e.Description))
+	}
+	line("END:VEVENT")
+	return buf.String(), nil
+}
+
+// Returns a QR Code of the event. The low error correction level is requested
+// to get the smallest symbol, the encoder raises it if the version allows.
+func (e Event) Encode() (qr.Generator, error) {
+	return encodePayload(e.Payload, qr.EccLow)
+}
+
+// Returns parameters and the value of a DTSTART or DTEND property.
+func (e Event) icalTime(t time.Time) string {
+	if e.AllDay {
+		return ";VALUE=DATE:" + t.Format(icalDate)
+	}
+	if loc := t.Location(); loc != time.UTC && loc != time.Local && loc.String() != "" {
+		return ";TZID=" + loc.String() + ":" + t.Format(icalDateTime)
+	}
+	return ":" + t.UTC().Format(icalDateTime) + "Z"
+}
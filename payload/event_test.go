@@ -0,0 +1,132 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+var (
+	testEventStart = time.Date(2018, 9, 20, 9, 30, 0, 0, time.UTC)
+	testEventStamp = time.Date(2018, 9, 1, 12, 0, 0, 0, testBerlin)
+	testBerlin     = time.FixedZone("Europe/Berlin", 2*60*60)
+)
+
+var EventPayload_TestData = []struct {
+	event    Event
+	expected string
+}{
+	{
+		event: Event{
+			UID:         "talk-42@conf.example.com",
+			Stamp:       testEventStamp,
+			Start:       testEventStart,
+			End:         testEventStart.Add(45 * time.Minute),
+			Summary:     "Keynote: QR, codes; more",
+			Location:    "Hall A",
+			Description: "Slides\\notes\nat the desk",
+		},
+		expected: "BEGIN:VEVENT\r\n" +
+			"UID:talk-42@conf.example.com\r\n" +
+			"DTSTAMP:20180901T100000Z\r\n" +
+			"DTSTART:20180920T093000Z\r\n" +
+			"DTEND:20180920T101500Z\r\n" +
+			"SUMMARY:Keynote: QR\\, codes\\; more\r\n" +
+			"LOCATION:Hall A\r\n" +
+			"DESCRIPTION:Slides\\\\notes\\nat the desk\r\n" +
+			"END:VEVENT\r\n",
+	},
+	{
+		event: Event{UID: "1", Stamp: testEventStamp, Start: testEventStart.In(testBerlin), End: testEventStart, Summary: "Lunch"},
+		expected: "BEGIN:VEVENT\r\n" +
+			"UID:1\r\n" +
+			"DTSTAMP:20180901T100000Z\r\n" +
+			"DTSTART;TZID=Europe/Berlin:20180920T113000\r\n" +
+			"SUMMARY:Lunch\r\n" +
+			"END:VEVENT\r\n",
+	},
+	{
+		event: Event{UID: "2", Stamp: testEventStamp, Start: testEventStart, End: testEventStart.AddDate(0, 0, 2), AllDay: true},
+		expected: "BEGIN:VEVENT\r\n" +
+			"UID:2\r\n" +
+			"DTSTAMP:20180901T100000Z\r\n" +
+			"DTSTART;VALUE=DATE:20180920\r\n" +
+			"DTEND;VALUE=DATE:20180923\r\n" +
+			"END:VEVENT\r\n",
+	},
+	{
+		event: Event{UID: "3", Stamp: testEventStamp, Start: testEventStart, End: testEventStart.Add(8 * time.Hour), AllDay: true},
+		expected: "BEGIN:VEVENT\r\n" +
+			"UID:3\r\n" +
+			"DTSTAMP:20180901T100000Z\r\n" +
+			"DTSTART;VALUE=DATE:20180920\r\n" +
+			"END:VEVENT\r\n",
+	},
+}
+
+func Test_EventPayload(test *testing.T) {
+	for i, data := range EventPayload_TestData {
+		if actual, err := data.event.Payload(); err != nil || actual != data.expected {
+			test.Errorf(
+				"event.Test_EventPayload[%d]:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+}
+
+var EventValidate_TestData = []struct {
+	event    Event
+	expected string
+}{
+	{event: Event{Start: testEventStart}, expected: "event has no UID"},
+	{event: Event{UID: "1"}, expected: "event has no start"},
+	{event: Event{UID: "1", Start: testEventStart, End: testEventStart.Add(-time.Hour)}, expected: "event ends before it starts"},
+}
+
+func Test_EventValidate(test *testing.T) {
+	for i, data := range EventValidate_TestData {
+		expected := payloadErr("Event.Validate", data.expected)
+		if actual := data.event.Validate(); actual == nil || actual.Error() != expected.Error() {
+			test.Errorf(
+				"event.Test_EventValidate[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, expected,
+			)
+		}
+		if _, err := data.event.Encode(); err == nil {
+			test.Errorf("event.Test_EventValidate[%d]:\n\tinvalid event is encoded", i)
+		}
+	}
+}
+
+func Test_EventPayloadStamp(test *testing.T) {
+	before := time.Now().UTC().Truncate(time.Second)
+	payload, err := Event{UID: "1", Start: testEventStart}.Payload()
+	if err != nil {
+		test.Fatalf("event.Test_EventPayloadStamp:\n\tunexpected error -> %s", err)
+	}
+	lines := strings.Split(payload, "\r\n")
+	stamp, err := time.Parse(icalDateTime+"Z", strings.TrimPrefix(lines[2], "DTSTAMP:"))
+	if err != nil || stamp.Before(before) || stamp.After(time.Now()) {
+		test.Errorf("event.Test_EventPayloadStamp:\n\tactual line -> %q (err: %v)\n is not the current time", lines[2], err)
+	}
+}
+
+func Test_EventEncode(test *testing.T) {
+	event := EventPayload_TestData[0].event
+	gen, err := event.Encode()
+	if err != nil {
+		test.Fatalf("event.Test_EventEncode:\n\tunexpected error -> %s", err)
+	}
+	payload, _ := event.Payload()
+	version, _ := gen.TextVersion(payload, qr.EccLow)
+	if actual := len(gen.GetModules()); actual != version*4+17 {
+		test.Errorf("event.Test_EventEncode:\n\tactual size -> %d\n is not equal to\n\texpected -> %d", actual, version*4+17)
+	}
+}
@@ -0,0 +1,266 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload // import "github.com/YuriyLisovskiy/qrcode/payload"
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+// Limits of EPC069-12 payloads.
+const (
+	maxGiroCodeLength      = 331
+	maxGiroCodeName        = 70
+	maxGiroCodeText        = 140
+	maxGiroCodeInformation = 70
+	maxGiroCodeAmount      = 99999999999 // 999999999.99 EUR in cents
+)
+
+// Error correction level mandated by EPC069-12.
+const giroCodeEcc = qr.EccMedium
+
+// Represents the character set of a GiroCode payload.
+type GiroCodeCharset int
+
+const (
+	// UTF-8, the default.
+	GiroCodeUTF8 GiroCodeCharset = 1
+
+	// ISO 8859-1 (Latin-1), understood by older banking applications.
+	GiroCodeLatin1 GiroCodeCharset = 2
+)
+
+// Describes a SEPA credit transfer as specified by the European Payments Council
+// in EPC069-12, also known as GiroCode:
+//
+//	BCD
+//	002
+//	1
+//	SCT
+//	COBADEFFXXX
+//	Acme GmbH
+//	DE89370400440532013000
+//	EUR12.3
+//	...
+type GiroCode struct {
+	// Version of the format, 1 or 2. Version 2 is used if zero.
+	// The BIC is mandatory in version 1.
+	Version int
+
+	// Character set of the payload, GiroCodeUTF8 if zero.
+	Charset GiroCodeCharset
+
+	// BIC of the beneficiary bank, 8 or 11 characters.
+	BIC string
+
+	// Name of the beneficiary, at most 70 characters.
+	Name string
+
+	// IBAN of the beneficiary, spaces are ignored.
+	IBAN string
+
+	// Amount in euro cents, from 1 to 99999999999. Zero leaves the amount to the payer.
+	Amount int64
+
+	// Purpose code of four uppercase letters, e.g. "CHAR" or "GDDS".
+	Purpose string
+
+	// Structured creditor reference (ISO 11649, e.g. "RF18539007547034") or unstructured
+	// remittance text of at most 140 characters. Only one of them may be set.
+	Reference, Text string
+
+	// Information to the payer, at most 70 characters.
+	Information string
+}
+
+// Returns an error if the transfer violates EPC069-12.
+func (g GiroCode) Validate() error {
+	_, err := g.payload("GiroCode.Validate")
+	return err
+}
+
+// Returns the payload of the transfer. Lines are separated by a line feed,
+// trailing empty lines are left out.
+func (g GiroCode) Payload() (string, error) {
+	return g.payload("GiroCode.Payload")
+}
+
+// Returns a QR Code of the transfer at the error correction level M mandated by EPC069-12.
+func (g GiroCode) Encode() (qr.Generator, error) {
+	text, err := g.Payload()
+	if err != nil {
+		return qr.Generator{}, err
+	}
+	gen := qr.Generator{}
+	return gen.EncodeTextExact(text, giroCodeEcc)
+}
+
+// Validates the transfer and builds its payload, reporting errors on behalf of method.
+func (g GiroCode) payload(method string) (string, error) {
+	version := g.Version
+	if version == 0 {
+		version = 2
+	}
+	if version != 1 && version != 2 {
+		return "", payloadErr(method, fmt.Sprintf("unsupported version %d", g.Version))
+	}
+	charset := g.Charset
+	if charset == 0 {
+		charset = GiroCodeUTF8
+	}
+	if charset != GiroCodeUTF8 && charset != GiroCodeLatin1 {
+		return "", payloadErr(method, fmt.Sprintf("unsupported character set %d", g.Charset))
+	}
+	if g.BIC == "" && version == 1 {
+		return "", payloadErr(method, "BIC is mandatory in version 1")
+	}
+	if g.BIC != "" && !isBIC(g.BIC) {
+		return "", payloadErr(method, fmt.Sprintf("invalid BIC '%s'", g.BIC))
+	}
+	if g.Name == "" || len([]rune(g.Name)) > maxGiroCodeName {
+		return "", payloadErr(method, fmt.Sprintf("name must be 1 to %d characters", maxGiroCodeName))
+	}
+	iban := strings.Replace(g.IBAN, " ", "", -1)
+	if !isIBAN(iban) {
+		return "", payloadErr(method, fmt.Sprintf("invalid IBAN '%s'", g.IBAN))
+	}
+	if g.Amount < 0 || g.Amount > maxGiroCodeAmount {
+		return "", payloadErr(method, "amount must be in range [0.01, 999999999.99] EUR")
+	}
+	if g.Purpose != "" && !isGiroCodePurpose(g.Purpose) {
+		return "", payloadErr(method, fmt.Sprintf("invalid purpose code '%s'", g.Purpose))
+	}
+	if g.Reference != "" && g.Text != "" {
+		return "", payloadErr(method, "reference and text are mutually exclusive")
+	}
+	if g.Reference != "" && !isCreditorReference(g.Reference) {
+		return "", payloadErr(method, fmt.Sprintf("invalid creditor reference '%s'", g.Reference))
+	}
+	if len([]rune(g.Text)) > maxGiroCodeText {
+		return "", payloadErr(method, fmt.Sprintf("text is longer than %d characters", maxGiroCodeText))
+	}
+	if len([]rune(g.Information)) > maxGiroCodeInformation {
+		return "", payloadErr(method, fmt.Sprintf("information is longer than %d characters", maxGiroCodeInformation))
+	}
+	lines := []string{
+		"BCD",
+		fmt.Sprintf("%03d", version),
+		fmt.Sprint(int(charset)),
+		"SCT",
+		g.BIC,
+		g.Name,
+		iban,
+		giroCodeAmount(g.Amount),
+		g.Purpose,
+		g.Reference,
+		g.Text,
+		g.Information,
+	}
+	for _, line := range lines {
+		if strings.ContainsAny(line, "\r\n") {
+			return "", payloadErr(method, "fields must not contain line breaks")
+		}
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	text := strings.Join(lines, "\n")
+	if charset == GiroCodeLatin1 {
+		latin1 := make([]byte, 0, len(text))
+		for _, c := range text {
+			if c > 0xFF {
+				return "", payloadErr(method, fmt.Sprintf("character '%c' is not in ISO 8859-1", c))
+			}
+			latin1 = append(latin1, byte(c))
+		}
+		text = string(latin1)
+	}
+	if len(text) > maxGiroCodeLength {
+		return "", payloadErr(method, fmt.Sprintf("payload is longer than %d bytes", maxGiroCodeLength))
+	}
+	return text, nil
+}
+
+// Returns the amount in euro cents as "EUR" followed by the amount without
+// trailing zeros, e.g. "EUR12.3", or nothing if the amount is zero.
+func giroCodeAmount(cents int64) string {
+	if cents == 0 {
+		return ""
+	}
+	amount := fmt.Sprintf("%d.%02d", cents/100, cents%100)
+	return "EUR" + strings.TrimSuffix(strings.TrimRight(amount, "0"), ".")
+}
+
+// Returns true if s is a BIC: four letters of the bank, two letters of the country,
+// two letters or digits of the location and optionally three of the branch.
+func isBIC(s string) bool {
+	if len(s) != 8 && len(s) != 11 {
+		return false
+	}
+	for i, c := range s {
+		if i < 6 && !isUpperLetter(c) || !isUpperLetter(c) && !isDigit(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if s is an IBAN: a country code, two check digits and up to
+// 30 letters or digits, with a valid ISO 7064 MOD 97-10 checksum.
+func isIBAN(s string) bool {
+	if len(s) < 15 || len(s) > 34 || !isUpperLetter(rune(s[0])) || !isUpperLetter(rune(s[1])) {
+		return false
+	}
+	return isDigit(rune(s[2])) && isDigit(rune(s[3])) && mod97(s[4:]+s[:4]) == 1
+}
+
+// Returns true if s is an ISO 11649 creditor reference: "RF", two check digits
+// and up to 21 letters or digits, with a valid ISO 7064 MOD 97-10 checksum.
+func isCreditorReference(s string) bool {
+	if len(s) < 5 || len(s) > 25 || !strings.HasPrefix(s, "RF") {
+		return false
+	}
+	return isDigit(rune(s[2])) && isDigit(rune(s[3])) && mod97(s[4:]+s[:4]) == 1
+}
+
+// Returns true if s is a purpose code of four uppercase letters.
+func isGiroCodePurpose(s string) bool {
+	if len(s) != 4 {
+		return false
+	}
+	for _, c := range s {
+		if !isUpperLetter(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the remainder of dividing s by 97, where letters stand for
+// the numbers 10 (A) to 35 (Z). Returns -1 if s has other characters.
+func mod97(s string) int {
+	remainder := 0
+	for _, c := range s {
+		switch {
+		case isDigit(c):
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case isUpperLetter(c):
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		default:
+			return -1
+		}
+	}
+	return remainder
+}
+
+func isDigit(c rune) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isUpperLetter(c rune) bool {
+	return 'A' <= c && c <= 'Z'
+}
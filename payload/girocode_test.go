@@ -0,0 +1,123 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload
+
+import (
+	"strings"
+	"testing"
+)
+
+var testGiroCode = GiroCode{
+	BIC:       "COBADEFFXXX",
+	Name:      "Acme GmbH",
+	IBAN:      "DE89 3704 0044 0532 0130 00",
+	Amount:    1230,
+	Reference: "RF18539007547034",
+}
+
+var GiroCodePayload_TestData = []struct {
+	giroCode GiroCode
+	expected string
+}{
+	{
+		giroCode: testGiroCode,
+		expected: "BCD\n002\n1\nSCT\nCOBADEFFXXX\nAcme GmbH\nDE89370400440532013000\nEUR12.3\n\nRF18539007547034",
+	},
+	{
+		giroCode: GiroCode{Version: 1, BIC: "COBADEFF", Name: "Ärzte e.V.", IBAN: "DE89370400440532013000",
+			Amount: 100000, Purpose: "CHAR", Text: "Donation", Information: "Thank you", Charset: GiroCodeLatin1},
+		expected: "BCD\n001\n2\nSCT\nCOBADEFF\n\xc4rzte e.V.\nDE89370400440532013000\nEUR1000\nCHAR\n\nDonation\nThank you",
+	},
+	{
+		giroCode: GiroCode{Name: "Acme", IBAN: "DE89370400440532013000"},
+		expected: "BCD\n002\n1\nSCT\n\nAcme\nDE89370400440532013000",
+	},
+}
+
+func Test_GiroCodePayload(test *testing.T) {
+	for i, data := range GiroCodePayload_TestData {
+		if actual, err := data.giroCode.Payload(); err != nil || actual != data.expected {
+			test.Errorf(
+				"girocode.Test_GiroCodePayload[%d]:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+}
+
+var GiroCodeValidate_TestData = []struct {
+	modify   func(g *GiroCode)
+	expected string
+}{
+	{modify: func(g *GiroCode) { g.Version = 3 }, expected: "unsupported version 3"},
+	{modify: func(g *GiroCode) { g.Charset = 9 }, expected: "unsupported character set 9"},
+	{modify: func(g *GiroCode) { g.Version, g.BIC = 1, "" }, expected: "BIC is mandatory in version 1"},
+	{modify: func(g *GiroCode) { g.BIC = "COBA1EFF" }, expected: "invalid BIC 'COBA1EFF'"},
+	{modify: func(g *GiroCode) { g.Name = "" }, expected: "name must be 1 to 70 characters"},
+	{modify: func(g *GiroCode) { g.IBAN = "DE88370400440532013000" }, expected: "invalid IBAN 'DE88370400440532013000'"},
+	{modify: func(g *GiroCode) { g.Amount = -1 }, expected: "amount must be in range [0.01, 999999999.99] EUR"},
+	{modify: func(g *GiroCode) { g.Purpose = "char" }, expected: "invalid purpose code 'char'"},
+	{modify: func(g *GiroCode) { g.Text = "Invoice 1" }, expected: "reference and text are mutually exclusive"},
+	{modify: func(g *GiroCode) { g.Reference = "RF19539007547034" }, expected: "invalid creditor reference 'RF19539007547034'"},
+	{modify: func(g *GiroCode) { g.Information = "a\nb" }, expected: "fields must not contain line breaks"},
+	{modify: func(g *GiroCode) { g.Charset, g.Name = GiroCodeLatin1, "Київ" }, expected: "character 'К' is not in ISO 8859-1"},
+	{
+		modify: func(g *GiroCode) {
+			g.Reference, g.Text, g.Information = "", strings.Repeat("t", 140), strings.Repeat("i", 70)
+			g.Name = strings.Repeat("n", 70)
+		},
+		expected: "payload is longer than 331 bytes",
+	},
+}
+
+func Test_GiroCodeValidate(test *testing.T) {
+	for i, data := range GiroCodeValidate_TestData {
+		giroCode := testGiroCode
+		data.modify(&giroCode)
+		expected := payloadErr("GiroCode.Validate", data.expected)
+		if actual := giroCode.Validate(); actual == nil || actual.Error() != expected.Error() {
+			test.Errorf(
+				"girocode.Test_GiroCodeValidate[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, expected,
+			)
+		}
+		if _, err := giroCode.Encode(); err == nil {
+			test.Errorf("girocode.Test_GiroCodeValidate[%d]:\n\tinvalid transfer is encoded", i)
+		}
+	}
+}
+
+func Test_GiroCodeEncode(test *testing.T) {
+	gen, err := testGiroCode.Encode()
+	if err != nil {
+		test.Fatalf("girocode.Test_GiroCodeEncode:\n\tunexpected error -> %s", err)
+	}
+	json, _ := gen.ToJson()
+	if !strings.Contains(json, "\"ecc\":\"M\"") {
+		test.Errorf("girocode.Test_GiroCodeEncode:\n\terror correction level is not M -> %s", json)
+	}
+}
+
+var mod97_TestData = []struct {
+	value    string
+	expected int
+}{
+	{value: "370400440532013000DE89", expected: 1},
+	{value: "539007547034RF18", expected: 1},
+	{value: "98", expected: 1},
+	{value: "A", expected: 10},
+	{value: "12-3", expected: -1},
+}
+
+func Test_mod97(test *testing.T) {
+	for i, data := range mod97_TestData {
+		if actual := mod97(data.value); actual != data.expected {
+			test.Errorf(
+				"girocode.Test_mod97[%d]:\n\tactual -> %d\n is not equal to\n\texpected -> %d",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
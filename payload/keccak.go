@@ -0,0 +1,84 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload // import "github.com/YuriyLisovskiy/qrcode/payload"
+
+import "encoding/binary"
+
+// Rate of Keccak-256 in bytes.
+const keccakRate = 136
+
+// Round constants of the Keccak-f[1600] permutation.
+var keccakRoundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// Rotation offsets and destination lanes of the combined rho and pi steps.
+var (
+	keccakRotations = [24]uint{1, 3, 6, 10, 15, 21, 28, 36, 45, 55, 2, 14, 27, 41, 56, 8, 25, 43, 62, 18, 39, 61, 20, 44}
+	keccakLanes     = [24]int{10, 7, 11, 17, 18, 3, 5, 16, 8, 21, 24, 4, 15, 23, 19, 13, 12, 2, 20, 14, 22, 9, 6, 1}
+)
+
+// Returns the Keccak-256 hash of data, as used by Ethereum. It differs from SHA3-256
+// in the padding only.
+func keccak256(data []byte) [32]byte {
+	var state [25]uint64
+	padded := make([]byte, (len(data)/keccakRate+1)*keccakRate)
+	copy(padded, data)
+	padded[len(data)] = 0x01
+	padded[len(padded)-1] |= 0x80
+	for block := padded; len(block) > 0; block = block[keccakRate:] {
+		for i := 0; i < keccakRate/8; i++ {
+			state[i] ^= binary.LittleEndian.Uint64(block[i*8:])
+		}
+		keccakF(&state)
+	}
+	var hash [32]byte
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint64(hash[i*8:], state[i])
+	}
+	return hash
+}
+
+// Applies the Keccak-f[1600] permutation to the state.
+func keccakF(a *[25]uint64) {
+	for round := 0; round < 24; round++ {
+		// Theta
+		var c [5]uint64
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d := c[(x+4)%5] ^ rotateLeft64(c[(x+1)%5], 1)
+			for y := 0; y < 25; y += 5 {
+				a[y+x] ^= d
+			}
+		}
+		// Rho and pi
+		current := a[1]
+		for i, lane := range keccakLanes {
+			current, a[lane] = a[lane], rotateLeft64(current, keccakRotations[i])
+		}
+		// Chi
+		for y := 0; y < 25; y += 5 {
+			var row [5]uint64
+			copy(row[:], a[y:y+5])
+			for x := 0; x < 5; x++ {
+				a[y+x] = row[x] ^ (^row[(x+1)%5] & row[(x+2)%5])
+			}
+		}
+		// Iota
+		a[0] ^= keccakRoundConstants[round]
+	}
+}
+
+// Returns x rotated left by k bits, k in the range [0, 63].
+func rotateLeft64(x uint64, k uint) uint64 {
+	return x<<k | x>>(64-k)
+}
@@ -0,0 +1,33 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+var keccak256_TestData = []struct {
+	data     string
+	expected string
+}{
+	{data: "", expected: "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+	{data: "abc", expected: "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+	// Exactly one block of data, so the padding takes a whole block.
+	{data: strings.Repeat("a", 136), expected: "a6c4d403279fe3e0af03729caada8374b5ca54d8065329a3ebcaeb4b60aa386e"},
+}
+
+func Test_keccak256(test *testing.T) {
+	for i, data := range keccak256_TestData {
+		hash := keccak256([]byte(data.data))
+		if actual := hex.EncodeToString(hash[:]); actual != data.expected {
+			test.Errorf(
+				"keccak.Test_keccak256[%d]:\n\tactual -> %s\n is not equal to\n\texpected -> %s",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload // import "github.com/YuriyLisovskiy/qrcode/payload"
+
+import (
+	"bytes"
+	"encoding/base32"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+// Supported hash algorithms of one-time passwords.
+var otpAlgorithms = map[string]bool{
+	"SHA1":   true,
+	"SHA256": true,
+	"SHA512": true,
+}
+
+// Represents the kind of one-time passwords.
+type OTPKind int
+
+const (
+	// Time-based one-time passwords (RFC 6238).
+	TOTP OTPKind = iota
+
+	// Counter-based one-time passwords (RFC 4226).
+	HOTP
+)
+
+// Names of kinds of one-time passwords in URIs.
+var otpKindNames = map[OTPKind]string{
+	TOTP: "totp",
+	HOTP: "hotp",
+}
+
+// Describes a one-time password generator provisioned to an authenticator
+// application with a Key URI:
+//
+//	otpauth://totp/Acme:john@acme.com?secret=JBSWY3DPEHPK3PXP&issuer=Acme
+//
+// Parameters left zero are not written, so applications use their defaults:
+// SHA1, 6 digits and a period of 30 seconds.
+type OTPAuth struct {
+	Kind OTPKind
+
+	// Provider and account the password is used for. Neither may contain a colon.
+	Issuer, Account string
+
+	// Shared secret, at least 10 bytes.
+	Secret []byte
+
+	// Hash algorithm: "SHA1", "SHA256" or "SHA512".
+	Algorithm string
+
+	// Number of digits of a password, 6 or 8.
+	Digits int
+
+	// Validity of a time-based password in seconds.
+	Period int
+
+	// Initial counter of counter-based passwords.
+	Counter uint64
+}
+
+// Returns an error if an application could not generate passwords with the parameters.
+func (o OTPAuth) Validate() error {
+	if _, ok := otpKindNames[o.Kind]; !ok {
+		return payloadErr("OTPAuth.Validate", fmt.Sprintf("unknown kind %d", o.Kind))
+	}
+	if o.Account == "" {
+		return payloadErr("OTPAuth.Validate", "account is empty")
+	}
+	if strings.Contains(o.Issuer, ":") || strings.Contains(o.Account, ":") {
+		return payloadErr("OTPAuth.Validate", "issuer and account must not contain a colon")
+	}
+	if len(o.Secret) < 10 {
+		return payloadErr("OTPAuth.Validate", "secret is shorter than 10 bytes")
+	}
+	if o.Algorithm != "" && !otpAlgorithms[o.Algorithm] {
+		return payloadErr("OTPAuth.Validate", fmt.Sprintf("unsupported algorithm '%s'", o.Algorithm))
+	}
+	if o.Digits != 0 && o.Digits != 6 && o.Digits != 8 {
+		return payloadErr("OTPAuth.Validate", "number of digits must be 6 or 8")
+	}
+	if o.Period < 0 {
+		return payloadErr("OTPAuth.Validate", "period must be positive")
+	}
+	if o.Kind == HOTP && o.Period != 0 {
+		return payloadErr("OTPAuth.Validate", "counter-based passwords have no period")
+	}
+	if o.Kind == TOTP && o.Counter != 0 {
+		return payloadErr("OTPAuth.Validate", "time-based passwords have no counter")
+	}
+	return nil
+}
+
+// Returns the Key URI of the generator. The issuer is written both as
+// the label prefix and as the parameter, as recommended for compatibility.
+func (o OTPAuth) Payload() (string, error) {
+	if err := o.Validate(); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "otpauth://%s/", otpKindNames[o.Kind])
+	if o.Issuer != "" {
+		buf.WriteString(pathEscape(o.Issuer) + ":")
+	}
+	buf.WriteString(pathEscape(o.Account))
+	// Authenticator applications expect secrets without padding.
+	fmt.Fprintf(&buf, "?secret=%s", strings.TrimRight(base32.StdEncoding.EncodeToString(o.Secret), "="))
+	if o.Issuer != "" {
+		fmt.Fprintf(&buf, "&issuer=%s", queryEscape(o.Issuer))
+	}
+	if o.Algorithm != "" {
+		fmt.Fprintf(&buf, "&algorithm=%s", o.Algorithm)
+	}
+	if o.Digits != 0 {
+		fmt.Fprintf(&buf, "&digits=%d", o.Digits)
+	}
+	if o.Period != 0 {
+		fmt.Fprintf(&buf, "&period=%d", o.Period)
+	}
+	if o.Kind == HOTP {
+		fmt.Fprintf(&buf, "&counter=%d", o.Counter)
+	}
+	return buf.String(), nil
+}
+
+// Returns a QR Code of the Key URI. Enrollment codes are scanned from screens,
+// so the medium error correction level is enough.
+func (o OTPAuth) Encode() (qr.Generator, error) {
+	return encodePayload(o.Payload, qr.EccMedium)
+}
+
+// Parses a Key URI. Secrets are accepted in any case, with or without padding.
+// Returns an error if the issuer parameter differs from the label prefix.
+func ParseOTPAuth(uri string) (OTPAuth, error) {
+	const method = "ParseOTPAuth"
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "otpauth" {
+		return OTPAuth{}, payloadErr(method, "not an otpauth URI")
+	}
+	var o OTPAuth
+	kind, ok := map[string]OTPKind{"totp": TOTP, "hotp": HOTP}[u.Host]
+	if !ok {
+		return OTPAuth{}, payloadErr(method, fmt.Sprintf("unknown kind '%s'", u.Host))
+	}
+	o.Kind = kind
+	label := strings.TrimPrefix(u.Path, "/")
+	if i := strings.Index(label, ":"); i != -1 {
+		o.Issuer, label = label[:i], label[i+1:]
+	}
+	o.Account = strings.TrimLeft(label, " ")
+	query := u.Query()
+	if issuer := query.Get("issuer"); issuer != "" {
+		if o.Issuer != "" && o.Issuer != issuer {
+			return OTPAuth{}, payloadErr(method, fmt.Sprintf("issuer '%s' differs from label prefix '%s'", issuer, o.Issuer))
+		}
+		o.Issuer = issuer
+	}
+	secret := strings.TrimRight(strings.ToUpper(query.Get("secret")), "=")
+	secret += strings.Repeat("=", (8-len(secret)%8)%8)
+	if o.Secret, err = base32.StdEncoding.DecodeString(secret); err != nil {
+		return OTPAuth{}, payloadErr(method, "secret is not base32 encoded")
+	}
+	o.Algorithm = query.Get("algorithm")
+	for _, param := range []struct {
+		name  string
+		value *int
+	}{{"digits", &o.Digits}, {"period", &o.Period}} {
+		if s := query.Get(param.name); s != "" {
+			if *param.value, err = strconv.Atoi(s); err != nil {
+				return OTPAuth{}, payloadErr(method, fmt.Sprintf("invalid %s '%s'", param.name, s))
+			}
+		}
+	}
+	if s := query.Get("counter"); s != "" {
+		if o.Counter, err = strconv.ParseUint(s, 10, 64); err != nil {
+			return OTPAuth{}, payloadErr(method, fmt.Sprintf("invalid counter '%s'", s))
+		}
+	} else if o.Kind == HOTP {
+		return OTPAuth{}, payloadErr(method, "counter-based URI has no counter")
+	}
+	if err := o.Validate(); err != nil {
+		return OTPAuth{}, err
+	}
+	return o, nil
+}
+
+// Escapes s to be used as a segment of a URI path. Same as url.PathEscape,
+// which is not available before Go 1.8.
+func pathEscape(s string) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~$&+:=@", c) != -1 {
+			buf.WriteByte(c)
+		} else {
+			fmt.Fprintf(&buf, "%%%02X", c)
+		}
+	}
+	return buf.String()
+}
+
+// Escapes s to be used as a query parameter value. Spaces are written as "%20",
+// which, unlike "+", is understood by all URI parsers.
+func queryEscape(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
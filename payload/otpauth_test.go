@@ -0,0 +1,159 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testOTPSecret = []byte("Hello!\xde\xad\xbe\xef")
+
+var OTPAuthPayload_TestData = []struct {
+	otp      OTPAuth
+	expected string
+}{
+	{
+		otp:      OTPAuth{Issuer: "Acme & Co", Account: "john@acme.com", Secret: testOTPSecret},
+		expected: "otpauth://totp/Acme%20&%20Co:john@acme.com?secret=JBSWY3DPEHPK3PXP&issuer=Acme%20%26%20Co",
+	},
+	{
+		otp: OTPAuth{Issuer: "Big/Corp", Account: "Jane Doe?", Secret: testOTPSecret,
+			Algorithm: "SHA256", Digits: 8, Period: 60},
+		expected: "otpauth://totp/Big%2FCorp:Jane%20Doe%3F?secret=JBSWY3DPEHPK3PXP&issuer=Big%2FCorp" +
+			"&algorithm=SHA256&digits=8&period=60",
+	},
+	{
+		otp:      OTPAuth{Kind: HOTP, Account: "alice", Secret: testOTPSecret, Counter: 42},
+		expected: "otpauth://hotp/alice?secret=JBSWY3DPEHPK3PXP&counter=42",
+	},
+	{
+		otp:      OTPAuth{Kind: HOTP, Account: "bob", Secret: testOTPSecret},
+		expected: "otpauth://hotp/bob?secret=JBSWY3DPEHPK3PXP&counter=0",
+	},
+}
+
+func Test_OTPAuthPayload(test *testing.T) {
+	for i, data := range OTPAuthPayload_TestData {
+		actual, err := data.otp.Payload()
+		if err != nil || actual != data.expected {
+			test.Errorf(
+				"otpauth.Test_OTPAuthPayload[%d]:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q",
+				i, actual, err, data.expected,
+			)
+			continue
+		}
+		parsed, err := ParseOTPAuth(actual)
+		if err != nil || !reflect.DeepEqual(parsed, data.otp) {
+			test.Errorf(
+				"otpauth.Test_OTPAuthPayload[%d]:\n\tactual parsed -> %+v (err: %v)\n is not equal to\n\texpected -> %+v",
+				i, parsed, err, data.otp,
+			)
+		}
+	}
+	if _, err := OTPAuthPayload_TestData[0].otp.Encode(); err != nil {
+		test.Errorf("otpauth.Test_OTPAuthPayload:\n\tunexpected error -> %s", err)
+	}
+}
+
+var OTPAuthValidate_TestData = []struct {
+	otp      OTPAuth
+	expected string
+}{
+	{otp: OTPAuth{Kind: 2, Account: "a", Secret: testOTPSecret}, expected: "unknown kind 2"},
+	{otp: OTPAuth{Secret: testOTPSecret}, expected: "account is empty"},
+	{otp: OTPAuth{Issuer: "A:B", Account: "a", Secret: testOTPSecret}, expected: "issuer and account must not contain a colon"},
+	{otp: OTPAuth{Account: "a", Secret: []byte("short")}, expected: "secret is shorter than 10 bytes"},
+	{otp: OTPAuth{Account: "a", Secret: testOTPSecret, Algorithm: "MD5"}, expected: "unsupported algorithm 'MD5'"},
+	{otp: OTPAuth{Account: "a", Secret: testOTPSecret, Digits: 7}, expected: "number of digits must be 6 or 8"},
+	{otp: OTPAuth{Account: "a", Secret: testOTPSecret, Period: -30}, expected: "period must be positive"},
+	{otp: OTPAuth{Kind: HOTP, Account: "a", Secret: testOTPSecret, Period: 30}, expected: "counter-based passwords have no period"},
+	{otp: OTPAuth{Account: "a", Secret: testOTPSecret, Counter: 1}, expected: "time-based passwords have no counter"},
+}
+
+func Test_OTPAuthValidate(test *testing.T) {
+	for i, data := range OTPAuthValidate_TestData {
+		expected := payloadErr("OTPAuth.Validate", data.expected)
+		if actual := data.otp.Validate(); actual == nil || actual.Error() != expected.Error() {
+			test.Errorf(
+				"otpauth.Test_OTPAuthValidate[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, expected,
+			)
+		}
+		if _, err := data.otp.Encode(); err == nil {
+			test.Errorf("otpauth.Test_OTPAuthValidate[%d]:\n\tinvalid generator is encoded", i)
+		}
+	}
+}
+
+var ParseOTPAuth_TestData = []struct {
+	uri      string
+	expected OTPAuth
+}{
+	{
+		// Lowercase padded secret and an encoded label separator with a space.
+		uri:      "otpauth://totp/Acme%3A%20john?secret=jbswy3dpehpk3pxp%3D%3D%3D%3D%3D%3D",
+		expected: OTPAuth{Issuer: "Acme", Account: "john", Secret: testOTPSecret},
+	},
+	{
+		uri:      "otpauth://totp/john?secret=JBSWY3DPEHPK3PXP&issuer=Acme",
+		expected: OTPAuth{Issuer: "Acme", Account: "john", Secret: testOTPSecret},
+	},
+}
+
+func Test_ParseOTPAuth(test *testing.T) {
+	for i, data := range ParseOTPAuth_TestData {
+		if actual, err := ParseOTPAuth(data.uri); err != nil || !reflect.DeepEqual(actual, data.expected) {
+			test.Errorf(
+				"otpauth.Test_ParseOTPAuth[%d]:\n\tactual -> %+v (err: %v)\n is not equal to\n\texpected -> %+v",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+}
+
+var ParseOTPAuthErr_TestData = []struct {
+	uri      string
+	expected string
+}{
+	{uri: "https://example.com", expected: "not an otpauth URI"},
+	{uri: "otpauth://motp/john?secret=JBSWY3DPEHPK3PXP", expected: "unknown kind 'motp'"},
+	{uri: "otpauth://totp/Acme:john?secret=JBSWY3DPEHPK3PXP&issuer=Other", expected: "issuer 'Other' differs from label prefix 'Acme'"},
+	{uri: "otpauth://totp/john?secret=JBSWY3DPEHPK3PX1", expected: "secret is not base32 encoded"},
+	{uri: "otpauth://totp/john?secret=JBSWY3DPEHPK3PXP&digits=six", expected: "invalid digits 'six'"},
+	{uri: "otpauth://hotp/john?secret=JBSWY3DPEHPK3PXP", expected: "counter-based URI has no counter"},
+}
+
+func Test_ParseOTPAuthErr(test *testing.T) {
+	for i, data := range ParseOTPAuthErr_TestData {
+		expected := payloadErr("ParseOTPAuth", data.expected)
+		if _, actual := ParseOTPAuth(data.uri); actual == nil || actual.Error() != expected.Error() {
+			test.Errorf(
+				"otpauth.Test_ParseOTPAuthErr[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, expected,
+			)
+		}
+	}
+}
+
+var pathEscape_TestData = []struct {
+	s        string
+	expected string
+}{
+	{s: "Acme & Co", expected: "Acme%20&%20Co"},
+	{s: "Big/Corp?", expected: "Big%2FCorp%3F"},
+	{s: "a;b,c", expected: "a%3Bb%2Cc"},
+	{s: "x@y.z:1=2+3$", expected: "x@y.z:1=2+3$"},
+	{s: "Zürich 100%", expected: "Z%C3%BCrich%20100%25"},
+	{s: "(!*')~", expected: "%28%21%2A%27%29~"},
+}
+
+func Test_pathEscape(test *testing.T) {
+	for i, data := range pathEscape_TestData {
+		if actual := pathEscape(data.s); actual != data.expected {
+			test.Errorf("otpauth.Test_pathEscape[%d]:\n\tactual -> %s\n is not equal to\n\texpected -> %s", i, actual, data.expected)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload // import "github.com/YuriyLisovskiy/qrcode/payload"
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+// Limits of the Swiss Payments Code.
+const (
+	maxSwissQRBillLength      = 997
+	maxSwissQRBillInformation = 140
+	maxSwissQRBillScheme      = 100
+	maxSwissQRBillSchemes     = 2
+	maxSwissQRBillAmount      = 99999999999 // 999999999.99 in cents
+)
+
+// Error correction level mandated by the Swiss Payments Code.
+const swissQRBillEcc = qr.EccMedium
+
+// Side of the Swiss cross as a fraction of the symbol side: 7 mm on a 46 mm symbol.
+const swissCrossRatio = 7.0 / 46
+
+// Proportions of the Swiss cross. The black square is inset into a white one by
+// a twentieth of its side, the white cross has the proportions of the Swiss flag:
+// arms of 6 by 20 units on a square of 32 units.
+const (
+	swissCrossInset     = 1.0 / 20
+	swissCrossArmWidth  = 6.0 / 32
+	swissCrossArmLength = 20.0 / 32
+)
+
+// Recursive modulo 10 table used by QR references.
+var qrReferenceTable = [10]int{0, 9, 4, 6, 8, 2, 7, 1, 3, 5}
+
+// Describes a structured address of a Swiss QR-bill.
+type SwissAddress struct {
+	// Name of a person or a company, at most 70 characters.
+	Name string
+
+	// Street and building number, at most 70 and 16 characters, may be empty.
+	Street, BuildingNumber string
+
+	// Postal code and town, at most 16 and 35 characters.
+	PostalCode, Town string
+
+	// Two-letter ISO 3166-1 country code, e.g. "CH".
+	Country string
+}
+
+// Describes a payment part of a Swiss QR-bill, encoded as a Swiss Payments Code
+// of version 0200. The reference type is derived from the account: a QR-IBAN
+// requires a QR reference, any other IBAN a creditor reference (ISO 11649) or none.
+type SwissQRBill struct {
+	// IBAN or QR-IBAN of the creditor in Switzerland or Liechtenstein, spaces are ignored.
+	IBAN string
+
+	Creditor SwissAddress
+
+	// Amount in cents, from 1 to 99999999999. Zero leaves the amount to the debtor.
+	Amount int64
+
+	// Currency of the amount, "CHF" or "EUR". "CHF" is used if empty.
+	Currency string
+
+	// Optional address of the debtor.
+	Debtor *SwissAddress
+
+	// QR reference of 27 digits or creditor reference, spaces are ignored.
+	Reference string
+
+	// Unstructured message and structured bill information, at most 140 characters together.
+	Message, BillInformation string
+
+	// Parameters of at most two alternative schemes, at most 100 characters each.
+	AlternativeSchemes []string
+}
+
+// Returns an error if the bill violates the Swiss Payments Code.
+func (b SwissQRBill) Validate() error {
+	_, err := b.payload("SwissQRBill.Validate")
+	return err
+}
+
+// Returns the Swiss Payments Code of the bill. Lines are separated by a line feed,
+// optional lines after the trailer are left out if empty.
+func (b SwissQRBill) Payload() (string, error) {
+	return b.payload("SwissQRBill.Payload")
+}
+
+// Returns a QR Code of the bill at the error correction level M mandated by
+// the Swiss Payments Code. Draw it with SwissQRImage or SwissQRSvg.
+func (b SwissQRBill) Encode() (qr.Generator, error) {
+	text, err := b.Payload()
+	if err != nil {
+		return qr.Generator{}, err
+	}
+	gen := qr.Generator{}
+	return gen.EncodeTextExact(text, swissQRBillEcc)
+}
+
+// Validates the bill and builds its payload, reporting errors on behalf of method.
+func (b SwissQRBill) payload(method string) (string, error) {
+	iban := strings.Replace(b.IBAN, " ", "", -1)
+	if !isIBAN(iban) || !strings.HasPrefix(iban, "CH") && !strings.HasPrefix(iban, "LI") || len(iban) != 21 {
+		return "", payloadErr(method, fmt.Sprintf("invalid Swiss IBAN '%s'", b.IBAN))
+	}
+	if err := b.Creditor.validate(method, "creditor"); err != nil {
+		return "", err
+	}
+	if b.Amount < 0 || b.Amount > maxSwissQRBillAmount {
+		return "", payloadErr(method, "amount must be in range [0.01, 999999999.99]")
+	}
+	currency := b.Currency
+	if currency == "" {
+		currency = "CHF"
+	}
+	if currency != "CHF" && currency != "EUR" {
+		return "", payloadErr(method, fmt.Sprintf("unsupported currency '%s'", b.Currency))
+	}
+	if b.Debtor != nil {
+		if err := b.Debtor.validate(method, "debtor"); err != nil {
+			return "", err
+		}
+	}
+	reference := strings.Replace(b.Reference, " ", "", -1)
+	referenceType := "NON"
+	switch {
+	case isQRIBAN(iban):
+		if !isQRReference(reference) {
+			return "", payloadErr(method, fmt.Sprintf("QR-IBAN requires a QR reference, got '%s'", b.Reference))
+		}
+		referenceType = "QRR"
+	case reference != "":
+		if !isCreditorReference(reference) {
+			return "", payloadErr(method, fmt.Sprintf("invalid creditor reference '%s'", b.Reference))
+		}
+		referenceType = "SCOR"
+	}
+	if len([]rune(b.Message+b.BillInformation)) > maxSwissQRBillInformation {
+		return "", payloadErr(method, fmt.Sprintf(
+			"message and bill information are longer than %d characters", maxSwissQRBillInformation,
+		))
+	}
+	if len(b.AlternativeSchemes) > maxSwissQRBillSchemes {
+		return "", payloadErr(method, fmt.Sprintf("more than %d alternative schemes", maxSwissQRBillSchemes))
+	}
+	for i, scheme := range b.AlternativeSchemes {
+		if len([]rune(scheme)) > maxSwissQRBillScheme {
+			return "", payloadErr(method, fmt.Sprintf(
+				"alternative scheme %d is longer than %d characters", i, maxSwissQRBillScheme,
+			))
+		}
+	}
+	amount := ""
+	if b.Amount != 0 {
+		amount = fmt.Sprintf("%d.%02d", b.Amount/100, b.Amount%100)
+	}
+	lines := []string{"SPC", "0200", "1", iban}
+	lines = append(lines, b.Creditor.lines()...)
+	// Ultimate creditor, reserved for future use.
+	lines = append(lines, make([]string, 7)...)
+	lines = append(lines, amount, currency)
+	if b.Debtor != nil {
+		lines = append(lines, b.Debtor.lines()...)
+	} else {
+		lines = append(lines, make([]string, 7)...)
+	}
+	lines = append(lines, referenceType, reference, b.Message, "EPD", b.BillInformation)
+	lines = append(lines, b.AlternativeSchemes...)
+	for _, line := range lines {
+		if strings.ContainsAny(line, "\r\n") {
+			return "", payloadErr(method, "fields must not contain line breaks")
+		}
+	}
+	for lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	text := strings.Join(lines, "\n")
+	if len([]rune(text)) > maxSwissQRBillLength {
+		return "", payloadErr(method, fmt.Sprintf("payload is longer than %d characters", maxSwissQRBillLength))
+	}
+	return text, nil
+}
+
+// Returns an error if a mandatory field of the address is empty or a field is too long.
+func (a SwissAddress) validate(method, party string) error {
+	fields := []struct {
+		name      string
+		value     string
+		max       int
+		mandatory bool
+	}{
+		{"name", a.Name, 70, true},
+		{"street", a.Street, 70, false},
+		{"building number", a.BuildingNumber, 16, false},
+		{"postal code", a.PostalCode, 16, true},
+		{"town", a.Town, 35, true},
+	}
+	for _, field := range fields {
+		if field.mandatory && field.value == "" {
+			return payloadErr(method, fmt.Sprintf("%s has no %s", party, field.name))
+		}
+		if len([]rune(field.value)) > field.max {
+			return payloadErr(method, fmt.Sprintf("%s %s is longer than %d characters", party, field.name, field.max))
+		}
+	}
+	if len(a.Country) != 2 || !isUpperLetter(rune(a.Country[0])) || !isUpperLetter(rune(a.Country[1])) {
+		return payloadErr(method, fmt.Sprintf("invalid %s country code '%s'", party, a.Country))
+	}
+	return nil
+}
+
+// Returns the lines of the address as a structured (type S) address.
+func (a SwissAddress) lines() []string {
+	return []string{"S", a.Name, a.Street, a.BuildingNumber, a.PostalCode, a.Town, a.Country}
+}
+
+// Returns true if the IBAN is a QR-IBAN, whose institution identification is in range 30000 to 31999.
+func isQRIBAN(iban string) bool {
+	iid := iban[4:9]
+	return iid >= "30000" && iid <= "31999"
+}
+
+// Returns true if s is a QR reference: 27 digits, the last of which is
+// the recursive modulo 10 check digit of the others.
+func isQRReference(s string) bool {
+	if len(s) != 27 {
+		return false
+	}
+	carry := 0
+	for _, c := range s[:26] {
+		if !isDigit(c) {
+			return false
+		}
+		carry = qrReferenceTable[(carry+int(c-'0'))%10]
+	}
+	return isDigit(rune(s[26])) && int(s[26]-'0') == (10-carry)%10
+}
+
+// Returns an error on behalf of method if gen is not encoded at error correction
+// level M, which the Swiss QR-bill requires.
+func checkSwissQRSymbol(method string, gen qr.Generator) error {
+	if len(gen.GetModules()) == 0 {
+		return payloadErr(method, "QR Code was not encoded")
+	}
+	if gen.GetErrorCorrectionLevel() != qr.EccMedium {
+		return payloadErr(method, "Swiss QR-bill requires error correction level M")
+	}
+	return nil
+}
+
+// Returns the given symbol drawn with the Swiss cross in the center. Every module
+// is moduleSize x moduleSize pixels, margin is measured in modules. The symbol
+// must be encoded at error correction level M (see SwissQRBill.Encode).
+func SwissQRImage(gen qr.Generator, margin, moduleSize uint) (image.Image, error) {
+	if moduleSize == 0 {
+		return nil, payloadErr("SwissQRImage", "module size must be positive")
+	}
+	if err := checkSwissQRSymbol("SwissQRImage", gen); err != nil {
+		return nil, err
+	}
+	modules := gen.GetModules()
+	cell := int(moduleSize)
+	offset := int(margin) * cell
+	side := (len(modules) + int(margin)*2) * cell
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for py := 0; py < cell; py++ {
+				for px := 0; px < cell; px++ {
+					img.SetGray(offset+x*cell+px, offset+y*cell+py, color.Gray{})
+				}
+			}
+		}
+	}
+	symbol := float64(len(modules) * cell)
+	crossSide := symbol * swissCrossRatio
+	start := float64(offset) + (symbol-crossSide)/2
+	for py := int(start); py <= int(start+crossSide); py++ {
+		for px := int(start); px <= int(start+crossSide); px++ {
+			x := (float64(px) + 0.5 - start) / crossSide
+			y := (float64(py) + 0.5 - start) / crossSide
+			if dark, ok := swissCrossPixel(x, y); ok {
+				c := color.Gray{Y: 255}
+				if dark {
+					c = color.Gray{}
+				}
+				img.SetGray(px, py, c)
+			}
+		}
+	}
+	return img, nil
+}
+
+// Returns svg string of the given symbol with the Swiss cross in the center.
+// The symbol must be encoded at error correction level M (see SwissQRBill.Encode).
+func SwissQRSvg(gen qr.Generator, border int) (string, error) {
+	if err := checkSwissQRSymbol("SwissQRSvg", gen); err != nil {
+		return "", err
+	}
+	svg, err := gen.ToSvg(border)
+	if err != nil {
+		return "", err
+	}
+	size := float64(len(gen.GetModules()))
+	side := size * swissCrossRatio
+	start := float64(border) + (size-side)/2
+	inset := side * swissCrossInset
+	inner := side - 2*inset
+	width := inner * swissCrossArmWidth
+	length := inner * swissCrossArmLength
+	center := start + side/2
+	var buf bytes.Buffer
+	f := formatSvgNumber
+	fmt.Fprintf(&buf, "\t<rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" fill=\"#FFFFFF\"/>\n", f(start), f(start), f(side), f(side))
+	fmt.Fprintf(&buf, "\t<rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" fill=\"#000000\"/>\n",
+		f(start+inset), f(start+inset), f(inner), f(inner))
+	fmt.Fprintf(&buf, "\t<path d=\"M%s,%sh%sv%sh%sz M%s,%sh%sv%sh%sz\" fill=\"#FFFFFF\"/>\n",
+		f(center-width/2), f(center-length/2), f(width), f(length), f(-width),
+		f(center-length/2), f(center-width/2), f(length), f(width), f(-length))
+	buf.WriteString("</svg>")
+	return strings.TrimSuffix(svg, "</svg>") + buf.String(), nil
+}
+
+// Returns a number of SVG coordinates rounded to 4 decimal places.
+func formatSvgNumber(v float64) string {
+	return strconv.FormatFloat(math.Floor(v*10000+0.5)/10000, 'f', -1, 64)
+}
+
+// Tells the color of the Swiss cross at the point (x, y), relative to the top left
+// corner of the cross and measured in its sides. Returns false if the point is outside.
+func swissCrossPixel(x, y float64) (dark, ok bool) {
+	if x < 0 || y < 0 || x >= 1 || y >= 1 {
+		return false, false
+	}
+	if x < swissCrossInset || y < swissCrossInset || x >= 1-swissCrossInset || y >= 1-swissCrossInset {
+		return false, true
+	}
+	// Distances from the center in sides of the black square.
+	dx := math.Abs(x-0.5) / (1 - 2*swissCrossInset)
+	dy := math.Abs(y-0.5) / (1 - 2*swissCrossInset)
+	inArm := func(across, along float64) bool {
+		return across < swissCrossArmWidth/2 && along < swissCrossArmLength/2
+	}
+	return !inArm(dx, dy) && !inArm(dy, dx), true
+}
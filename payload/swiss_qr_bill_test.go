@@ -0,0 +1,233 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload
+
+import (
+	"image/color"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+var testSwissCreditor = SwissAddress{
+	Name: "Robert Schneider AG", Street: "Rue du Lac", BuildingNumber: "1268",
+	PostalCode: "2501", Town: "Biel", Country: "CH",
+}
+
+var testSwissQRBill = SwissQRBill{
+	IBAN:      "CH44 3199 9123 0008 8901 2",
+	Creditor:  testSwissCreditor,
+	Amount:    194975,
+	Debtor:    &SwissAddress{Name: "Pia-Maria Rutschmann-Schnyder", Street: "Grosse Marktgasse", BuildingNumber: "28", PostalCode: "9400", Town: "Rorschach", Country: "CH"},
+	Reference: "21 00000 00003 13947 14300 09017",
+	Message:   "Order of 15.06.2020",
+}
+
+var SwissQRBillPayload_TestData = []struct {
+	bill     SwissQRBill
+	expected string
+}{
+	{
+		bill: testSwissQRBill,
+		expected: "SPC\n0200\n1\nCH4431999123000889012\n" +
+			"S\nRobert Schneider AG\nRue du Lac\n1268\n2501\nBiel\nCH\n" +
+			"\n\n\n\n\n\n\n" +
+			"1949.75\nCHF\n" +
+			"S\nPia-Maria Rutschmann-Schnyder\nGrosse Marktgasse\n28\n9400\nRorschach\nCH\n" +
+			"QRR\n210000000003139471430009017\nOrder of 15.06.2020\nEPD",
+	},
+	{
+		bill: SwissQRBill{
+			IBAN: "CH9300762011623852957", Creditor: testSwissCreditor, Currency: "EUR",
+			Reference: "RF18539007547034", BillInformation: "//S1/10/10201409", AlternativeSchemes: []string{"eBill/B/41010560425610173"},
+		},
+		expected: "SPC\n0200\n1\nCH9300762011623852957\n" +
+			"S\nRobert Schneider AG\nRue du Lac\n1268\n2501\nBiel\nCH\n" +
+			"\n\n\n\n\n\n\n" +
+			"\nEUR\n" +
+			"\n\n\n\n\n\n\n" +
+			"SCOR\nRF18539007547034\n\nEPD\n//S1/10/10201409\neBill/B/41010560425610173",
+	},
+	{
+		bill: SwissQRBill{IBAN: "CH9300762011623852957", Creditor: testSwissCreditor, Amount: 5},
+		expected: "SPC\n0200\n1\nCH9300762011623852957\n" +
+			"S\nRobert Schneider AG\nRue du Lac\n1268\n2501\nBiel\nCH\n" +
+			"\n\n\n\n\n\n\n" +
+			"0.05\nCHF\n" +
+			"\n\n\n\n\n\n\n" +
+			"NON\n\n\nEPD",
+	},
+}
+
+func Test_SwissQRBillPayload(test *testing.T) {
+	for i, data := range SwissQRBillPayload_TestData {
+		if actual, err := data.bill.Payload(); err != nil || actual != data.expected {
+			test.Errorf(
+				"swiss_qr_bill.Test_SwissQRBillPayload[%d]:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+}
+
+var SwissQRBillValidate_TestData = []struct {
+	modify   func(b *SwissQRBill)
+	expected string
+}{
+	{modify: func(b *SwissQRBill) { b.IBAN = "DE89370400440532013000" }, expected: "invalid Swiss IBAN 'DE89370400440532013000'"},
+	{modify: func(b *SwissQRBill) { b.IBAN = "CH4531999123000889012" }, expected: "invalid Swiss IBAN 'CH4531999123000889012'"},
+	{modify: func(b *SwissQRBill) { b.Creditor.Town = "" }, expected: "creditor has no town"},
+	{modify: func(b *SwissQRBill) { b.Debtor.Country = "ch" }, expected: "invalid debtor country code 'ch'"},
+	{modify: func(b *SwissQRBill) { b.Debtor.BuildingNumber = strings.Repeat("1", 17) }, expected: "debtor building number is longer than 16 characters"},
+	{modify: func(b *SwissQRBill) { b.Amount = -5 }, expected: "amount must be in range [0.01, 999999999.99]"},
+	{modify: func(b *SwissQRBill) { b.Currency = "USD" }, expected: "unsupported currency 'USD'"},
+	{
+		modify:   func(b *SwissQRBill) { b.Reference = "210000000003139471430009018" },
+		expected: "QR-IBAN requires a QR reference, got '210000000003139471430009018'",
+	},
+	{
+		modify:   func(b *SwissQRBill) { b.IBAN, b.Reference = "CH9300762011623852957", "210000000003139471430009017" },
+		expected: "invalid creditor reference '210000000003139471430009017'",
+	},
+	{modify: func(b *SwissQRBill) { b.BillInformation = strings.Repeat("i", 125) }, expected: "message and bill information are longer than 140 characters"},
+	{modify: func(b *SwissQRBill) { b.AlternativeSchemes = []string{"a", "b", "c"} }, expected: "more than 2 alternative schemes"},
+	{modify: func(b *SwissQRBill) { b.Message = "a\nb" }, expected: "fields must not contain line breaks"},
+}
+
+func Test_SwissQRBillValidate(test *testing.T) {
+	for i, data := range SwissQRBillValidate_TestData {
+		bill := testSwissQRBill
+		debtor := *bill.Debtor
+		bill.Debtor = &debtor
+		data.modify(&bill)
+		expected := payloadErr("SwissQRBill.Validate", data.expected)
+		if actual := bill.Validate(); actual == nil || actual.Error() != expected.Error() {
+			test.Errorf(
+				"swiss_qr_bill.Test_SwissQRBillValidate[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, expected,
+			)
+		}
+		if _, err := bill.Encode(); err == nil {
+			test.Errorf("swiss_qr_bill.Test_SwissQRBillValidate[%d]:\n\tinvalid bill is encoded", i)
+		}
+	}
+}
+
+var isQRReference_TestData = []struct {
+	reference string
+	expected  bool
+}{
+	{reference: "210000000003139471430009017", expected: true},
+	{reference: "000000000000000000000000000", expected: true},
+	{reference: "210000000003139471430009018", expected: false},
+	{reference: "21000000000313947143000901", expected: false},
+	{reference: "2100000000031394714300090A7", expected: false},
+}
+
+func Test_isQRReference(test *testing.T) {
+	for i, data := range isQRReference_TestData {
+		if actual := isQRReference(data.reference); actual != data.expected {
+			test.Errorf(
+				"swiss_qr_bill.Test_isQRReference[%d]:\n\tactual -> %t\n is not equal to\n\texpected -> %t",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+func Test_SwissQRImage(test *testing.T) {
+	gen, err := testSwissQRBill.Encode()
+	if err != nil {
+		test.Fatalf("swiss_qr_bill.Test_SwissQRImage:\n\tunexpected error -> %s", err)
+	}
+	json, _ := gen.ToJson()
+	if !strings.Contains(json, "\"ecc\":\"M\"") {
+		test.Errorf("swiss_qr_bill.Test_SwissQRImage:\n\terror correction level is not M -> %s", json)
+	}
+	const margin, moduleSize = 4, 10
+	img, err := SwissQRImage(gen, margin, moduleSize)
+	if err != nil {
+		test.Fatalf("swiss_qr_bill.Test_SwissQRImage:\n\tunexpected error -> %s", err)
+	}
+	size := len(gen.GetModules())
+	symbol := float64(size * moduleSize)
+	center := margin*moduleSize + size*moduleSize/2
+	crossSide := symbol * swissCrossRatio
+	gray := func(x, y int) uint8 {
+		return color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y
+	}
+	// The center of the cross is white, the corners of the black square are black,
+	// the white border surrounds it.
+	points := []struct {
+		dx, dy   float64
+		expected uint8
+	}{
+		{dx: 0, dy: 0, expected: 255},
+		{dx: 0.4, dy: 0.4, expected: 0},
+		{dx: -0.4, dy: 0.4, expected: 0},
+		{dx: 0, dy: -0.25, expected: 255},
+		{dx: 0.49, dy: 0, expected: 255},
+	}
+	for j, p := range points {
+		x, y := center+int(p.dx*crossSide), center+int(p.dy*crossSide)
+		if actual := gray(x, y); actual != p.expected {
+			test.Errorf(
+				"swiss_qr_bill.Test_SwissQRImage[%d]:\n\tactual gray at (%d, %d) -> %d\n is not equal to\n\texpected -> %d",
+				j, x, y, actual, p.expected,
+			)
+		}
+	}
+	// Modules away from the cross are drawn as usual.
+	modules := gen.GetModules()
+	for y := 0; y < size; y++ {
+		for x := 0; x < 9; x++ {
+			expected := uint8(255)
+			if modules[y][x] {
+				expected = 0
+			}
+			if actual := gray((margin+x)*moduleSize+moduleSize/2, (margin+y)*moduleSize+moduleSize/2); actual != expected {
+				test.Fatalf("swiss_qr_bill.Test_SwissQRImage:\n\tmodule (%d, %d) is not drawn", x, y)
+			}
+		}
+	}
+	if _, err := SwissQRImage(gen, margin, 0); err == nil {
+		test.Errorf("swiss_qr_bill.Test_SwissQRImage:\n\tzero module size is accepted")
+	}
+	payload, _ := testSwissQRBill.Payload()
+	low, _ := gen.EncodeTextExact(payload, qr.EccLow)
+	expected := payloadErr("SwissQRImage", "Swiss QR-bill requires error correction level M")
+	if _, err := SwissQRImage(low, margin, moduleSize); err == nil || err.Error() != expected.Error() {
+		test.Errorf("swiss_qr_bill.Test_SwissQRImage:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v", err, expected)
+	}
+}
+
+func Test_SwissQRSvg(test *testing.T) {
+	bill := SwissQRBillPayload_TestData[2].bill
+	gen, err := bill.Encode()
+	if err != nil {
+		test.Fatalf("swiss_qr_bill.Test_SwissQRSvg:\n\tunexpected error -> %s", err)
+	}
+	svg, err := SwissQRSvg(gen, 4)
+	if err != nil {
+		test.Fatalf("swiss_qr_bill.Test_SwissQRSvg:\n\tunexpected error -> %s", err)
+	}
+	if !strings.HasSuffix(svg, "fill=\"#FFFFFF\"/>\n</svg>") || strings.Count(svg, "<rect") != 3 {
+		test.Errorf("swiss_qr_bill.Test_SwissQRSvg:\n\tcross is not drawn on top -> %s", svg)
+	}
+	// Coordinates are rounded like in other SVG renderers.
+	if digits := regexp.MustCompile(`\.\d{5,}`).FindString(svg); digits != "" {
+		test.Errorf("swiss_qr_bill.Test_SwissQRSvg:\n\tcoordinate is not rounded -> %s", digits)
+	}
+	if _, err := SwissQRSvg(gen, -1); err == nil {
+		test.Errorf("swiss_qr_bill.Test_SwissQRSvg:\n\tnegative border is accepted")
+	}
+	payload, _ := bill.Payload()
+	high, _ := gen.EncodeTextExact(payload, qr.EccHigh)
+	if _, err := SwissQRSvg(high, 4); err == nil {
+		test.Errorf("swiss_qr_bill.Test_SwissQRSvg:\n\tsymbol at error correction level H is accepted")
+	}
+}
@@ -0,0 +1,326 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload // import "github.com/YuriyLisovskiy/qrcode/payload"
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+// Builders of this file write case insensitive parts of their payloads, such as
+// schemes and host names, in uppercase. Uppercase letters, digits and some
+// punctuation are encoded in the alphanumeric mode with 5.5 bits per character
+// instead of 8, so with mixed segmentation short links fit into smaller symbols.
+
+// Visual separators allowed in phone numbers, dropped from the payload.
+const phoneSeparators = " -.()"
+
+// Describes a geographic location, encoded as a geo URI (RFC 5870):
+//
+//	GEO:48.2010,16.3695,183;U=40
+type Geo struct {
+	// Latitude and longitude in degrees (WGS-84).
+	Latitude, Longitude float64
+
+	// Optional altitude in meters.
+	Altitude *float64
+
+	// Uncertainty of the location in meters, zero if unknown.
+	Uncertainty float64
+}
+
+// Returns an error if a coordinate is out of range or the uncertainty is negative.
+func (g Geo) Validate() error {
+	if math.IsNaN(g.Latitude) || g.Latitude < -90 || g.Latitude > 90 {
+		return payloadErr("Geo.Validate", "latitude must be in range [-90, 90]")
+	}
+	if math.IsNaN(g.Longitude) || g.Longitude < -180 || g.Longitude > 180 {
+		return payloadErr("Geo.Validate", "longitude must be in range [-180, 180]")
+	}
+	if g.Altitude != nil && (math.IsNaN(*g.Altitude) || math.IsInf(*g.Altitude, 0)) {
+		return payloadErr("Geo.Validate", "altitude must be a finite number")
+	}
+	if math.IsNaN(g.Uncertainty) || g.Uncertainty < 0 || math.IsInf(g.Uncertainty, 0) {
+		return payloadErr("Geo.Validate", "uncertainty must be a finite non-negative number")
+	}
+	return nil
+}
+
+// Returns the geo URI of the location.
+func (g Geo) Payload() (string, error) {
+	if err := g.Validate(); err != nil {
+		return "", err
+	}
+	payload := "GEO:" + formatCoordinate(g.Latitude) + "," + formatCoordinate(g.Longitude)
+	if g.Altitude != nil {
+		payload += "," + formatCoordinate(*g.Altitude)
+	}
+	if g.Uncertainty != 0 {
+		payload += ";U=" + formatCoordinate(g.Uncertainty)
+	}
+	return payload, nil
+}
+
+// Returns a QR Code of the location.
+func (g Geo) Encode() (qr.Generator, error) {
+	return encodeMixedPayload(g.Payload, qr.EccMedium)
+}
+
+// Describes a phone number, encoded as a tel URI (RFC 3966):
+//
+//	TEL:+15550100
+type Phone struct {
+	// Global number starting with "+" or a local number. Spaces, hyphens,
+	// periods and parentheses are allowed as separators.
+	Number string
+}
+
+// Returns an error if the number has characters other than digits and separators.
+func (p Phone) Validate() error {
+	if _, err := normalizePhone(p.Number); err != nil {
+		return payloadErr("Phone.Validate", err.Error())
+	}
+	return nil
+}
+
+// Returns the tel URI of the number without separators.
+func (p Phone) Payload() (string, error) {
+	number, err := normalizePhone(p.Number)
+	if err != nil {
+		return "", payloadErr("Phone.Payload", err.Error())
+	}
+	return "TEL:" + number, nil
+}
+
+// Returns a QR Code of the number.
+func (p Phone) Encode() (qr.Generator, error) {
+	return encodeMixedPayload(p.Payload, qr.EccMedium)
+}
+
+// Describes a text message to be sent to a phone number. By default it is encoded
+// in the SMSTO format understood by barcode scanners:
+//
+//	SMSTO:+15550100:See you at 5
+//
+// or, if URI is set, as an sms URI (RFC 5724):
+//
+//	SMS:+15550100?body=See%20you%20at%205
+type SMS struct {
+	// Phone number of the recipient, as in Phone.
+	Number string
+
+	// Optional text of the message.
+	Message string
+
+	// Selects the sms URI instead of the SMSTO format.
+	URI bool
+}
+
+// Returns an error if the number is invalid.
+func (s SMS) Validate() error {
+	if _, err := normalizePhone(s.Number); err != nil {
+		return payloadErr("SMS.Validate", err.Error())
+	}
+	return nil
+}
+
+// Returns the payload of the message. The message is written as is in the SMSTO
+// format and percent-encoded in the sms URI.
+func (s SMS) Payload() (string, error) {
+	number, err := normalizePhone(s.Number)
+	if err != nil {
+		return "", payloadErr("SMS.Payload", err.Error())
+	}
+	if !s.URI {
+		if s.Message == "" {
+			return "SMSTO:" + number, nil
+		}
+		return "SMSTO:" + number + ":" + s.Message, nil
+	}
+	if s.Message == "" {
+		return "SMS:" + number, nil
+	}
+	return "SMS:" + number + "?body=" + queryEscape(s.Message), nil
+}
+
+// Returns a QR Code of the message.
+func (s SMS) Encode() (qr.Generator, error) {
+	return encodeMixedPayload(s.Payload, qr.EccMedium)
+}
+
+// Describes an email, encoded as a mailto URI (RFC 6068):
+//
+//	MAILTO:john@ACME.COM?subject=Hello
+type Email struct {
+	// Address of the recipient. The local part is kept as is,
+	// the domain is written in uppercase.
+	Address string
+
+	Subject, Body string
+}
+
+// Returns an error if the address has no local part or domain.
+func (e Email) Validate() error {
+	at := strings.LastIndex(e.Address, "@")
+	if at < 1 || at == len(e.Address)-1 || strings.ContainsAny(e.Address[at+1:], " /?#%") {
+		return payloadErr("Email.Validate", fmt.Sprintf("invalid address '%s'", e.Address))
+	}
+	return nil
+}
+
+// Returns the mailto URI of the email. Characters of the local part which
+// have a meaning in URIs are percent-encoded.
+func (e Email) Payload() (string, error) {
+	if err := e.Validate(); err != nil {
+		return "", err
+	}
+	at := strings.LastIndex(e.Address, "@")
+	var buf bytes.Buffer
+	buf.WriteString("MAILTO:")
+	for _, c := range []byte(e.Address[:at]) {
+		if strings.IndexByte("%/?#&", c) != -1 || c <= ' ' || c >= 0x7F {
+			fmt.Fprintf(&buf, "%%%02X", c)
+		} else {
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteString("@" + upperASCII(e.Address[at+1:]))
+	var params []string
+	if e.Subject != "" {
+		params = append(params, "subject="+queryEscape(e.Subject))
+	}
+	if e.Body != "" {
+		params = append(params, "body="+queryEscape(e.Body))
+	}
+	if len(params) > 0 {
+		buf.WriteString("?" + strings.Join(params, "&"))
+	}
+	return buf.String(), nil
+}
+
+// Returns a QR Code of the email.
+func (e Email) Encode() (qr.Generator, error) {
+	return encodeMixedPayload(e.Payload, qr.EccMedium)
+}
+
+// Describes a link to a web page or any other hierarchical URI:
+//
+//	HTTPS://EXAMPLE.COM/Path?q=1
+type Link struct {
+	// Absolute URI with a scheme and a host, e.g. "https://example.com/Path?q=1".
+	URL string
+}
+
+// Returns an error if the URL has no scheme or host, or contains whitespace.
+func (l Link) Validate() error {
+	if _, _, err := splitLink(l.URL); err != nil {
+		return payloadErr("Link.Validate", err.Error())
+	}
+	return nil
+}
+
+// Returns the URL with the scheme and the host in uppercase. User information,
+// the path, the query and the fragment are case sensitive and kept as is.
+func (l Link) Payload() (string, error) {
+	authority, rest, err := splitLink(l.URL)
+	if err != nil {
+		return "", payloadErr("Link.Payload", err.Error())
+	}
+	scheme := l.URL[:strings.Index(l.URL, "://")]
+	host := authority
+	user := ""
+	if at := strings.LastIndex(authority, "@"); at != -1 {
+		user, host = authority[:at+1], authority[at+1:]
+	}
+	return upperASCII(scheme) + "://" + user + upperASCII(host) + rest, nil
+}
+
+// Returns a QR Code of the link.
+func (l Link) Encode() (qr.Generator, error) {
+	return encodeMixedPayload(l.Payload, qr.EccMedium)
+}
+
+// Encodes the result of the given payload builder at the given error correction level or higher,
+// splitting it into segments of different modes where it makes the symbol smaller. URIs are
+// written with an uppercase scheme and host, which only pays off if those are encoded in the
+// alphanumeric mode while the case-sensitive rest is not.
+func encodeMixedPayload(build func() (string, error), ecl qr.EccLevel) (qr.Generator, error) {
+	text, err := build()
+	if err != nil {
+		return qr.Generator{}, err
+	}
+	gen := qr.Generator{}
+	return gen.EncodeMixedText(text, ecl)
+}
+
+// Splits an absolute URL into the authority and the rest after it.
+func splitLink(link string) (authority, rest string, err error) {
+	i := strings.Index(link, "://")
+	if i < 1 || !isScheme(link[:i]) {
+		return "", "", fmt.Errorf("URL '%s' has no scheme", link)
+	}
+	if strings.ContainsAny(link, " \t\r\n") {
+		return "", "", fmt.Errorf("URL '%s' contains whitespace", link)
+	}
+	authority = link[i+3:]
+	if end := strings.IndexAny(authority, "/?#"); end != -1 {
+		authority, rest = authority[:end], authority[end:]
+	}
+	if authority == "" || strings.HasSuffix(authority, "@") {
+		return "", "", fmt.Errorf("URL '%s' has no host", link)
+	}
+	return authority, rest, nil
+}
+
+// Returns true if s is a URI scheme: a letter followed by letters, digits, "+", "-" or ".".
+func isScheme(s string) bool {
+	for i, c := range s {
+		letter := 'a' <= c && c <= 'z' || isUpperLetter(c)
+		if !letter && (i == 0 || !isDigit(c) && !strings.ContainsRune("+-.", c)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Returns the phone number without visual separators. Returns an error if it has
+// other characters than digits and separators, or a "+" anywhere but at the start.
+func normalizePhone(number string) (string, error) {
+	var buf bytes.Buffer
+	for i, c := range number {
+		switch {
+		case isDigit(c), c == '+' && i == 0:
+			buf.WriteRune(c)
+		case strings.ContainsRune(phoneSeparators, c):
+		default:
+			return "", fmt.Errorf("invalid phone number '%s'", number)
+		}
+	}
+	if buf.Len() == 0 || buf.String() == "+" {
+		return "", fmt.Errorf("invalid phone number '%s'", number)
+	}
+	return buf.String(), nil
+}
+
+// Returns a coordinate with as many decimal digits as needed.
+func formatCoordinate(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// Returns s with ASCII letters in uppercase. Other letters are kept, since
+// they are not encoded in the alphanumeric mode anyway.
+func upperASCII(s string) string {
+	return strings.Map(func(c rune) rune {
+		if 'a' <= c && c <= 'z' {
+			return c - 'a' + 'A'
+		}
+		return c
+	}, s)
+}
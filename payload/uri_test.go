@@ -0,0 +1,101 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload
+
+import (
+	"math"
+	"testing"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+type payloadBuilder interface {
+	Payload() (string, error)
+	Encode() (qr.Generator, error)
+}
+
+var testAltitude = 183.0
+
+var URIPayload_TestData = []struct {
+	builder  payloadBuilder
+	expected string
+}{
+	{builder: Geo{Latitude: 48.201, Longitude: 16.3695}, expected: "GEO:48.201,16.3695"},
+	{builder: Geo{Latitude: -33.8568, Longitude: 151.2153, Altitude: &testAltitude, Uncertainty: 40}, expected: "GEO:-33.8568,151.2153,183;U=40"},
+	{builder: Phone{Number: "+1 (555) 010-0"}, expected: "TEL:+15550100"},
+	{builder: SMS{Number: "+1 555 0100"}, expected: "SMSTO:+15550100"},
+	{builder: SMS{Number: "+1 555 0100", Message: "See you at 5: lobby"}, expected: "SMSTO:+15550100:See you at 5: lobby"},
+	{builder: SMS{Number: "0100", Message: "See you & bye", URI: true}, expected: "SMS:0100?body=See%20you%20%26%20bye"},
+	{builder: SMS{Number: "0100", URI: true}, expected: "SMS:0100"},
+	{builder: Email{Address: "John.Doe@acme.com"}, expected: "MAILTO:John.Doe@ACME.COM"},
+	{
+		builder:  Email{Address: "sales&support@acme.com", Subject: "Order #12", Body: "Hi,\nthanks"},
+		expected: "MAILTO:sales%26support@ACME.COM?subject=Order%20%2312&body=Hi%2C%0Athanks",
+	},
+	{builder: Link{URL: "https://bit.ly/AbC"}, expected: "HTTPS://BIT.LY/AbC"},
+	{builder: Link{URL: "https://User:Pw@example.com:8080"}, expected: "HTTPS://User:Pw@EXAMPLE.COM:8080"},
+	{builder: Link{URL: "ftp://files.example.com/Docs/a.txt?x=Y#Top"}, expected: "FTP://FILES.EXAMPLE.COM/Docs/a.txt?x=Y#Top"},
+	{builder: Link{URL: "https://bücher.example/"}, expected: "HTTPS://BüCHER.EXAMPLE/"},
+}
+
+func Test_URIPayload(test *testing.T) {
+	for i, data := range URIPayload_TestData {
+		if actual, err := data.builder.Payload(); err != nil || actual != data.expected {
+			test.Errorf(
+				"uri.Test_URIPayload[%d]:\n\tactual -> %q (err: %v)\n is not equal to\n\texpected -> %q",
+				i, actual, err, data.expected,
+			)
+		}
+		if _, err := data.builder.Encode(); err != nil {
+			test.Errorf("uri.Test_URIPayload[%d]:\n\tunexpected error -> %s", i, err)
+		}
+	}
+}
+
+var URIValidate_TestData = []struct {
+	builder  interface{ Validate() error }
+	expected error
+}{
+	{builder: Geo{Latitude: 91}, expected: payloadErr("Geo.Validate", "latitude must be in range [-90, 90]")},
+	{builder: Geo{Longitude: math.NaN()}, expected: payloadErr("Geo.Validate", "longitude must be in range [-180, 180]")},
+	{builder: Geo{Uncertainty: -1}, expected: payloadErr("Geo.Validate", "uncertainty must be a finite non-negative number")},
+	{builder: Phone{Number: "+"}, expected: payloadErr("Phone.Validate", "invalid phone number '+'")},
+	{builder: Phone{Number: "555+0100"}, expected: payloadErr("Phone.Validate", "invalid phone number '555+0100'")},
+	{builder: SMS{Number: "call me"}, expected: payloadErr("SMS.Validate", "invalid phone number 'call me'")},
+	{builder: Email{Address: "@acme.com"}, expected: payloadErr("Email.Validate", "invalid address '@acme.com'")},
+	{builder: Email{Address: "john@acme.com?x"}, expected: payloadErr("Email.Validate", "invalid address 'john@acme.com?x'")},
+	{builder: Link{URL: "example.com/page"}, expected: payloadErr("Link.Validate", "URL 'example.com/page' has no scheme")},
+	{builder: Link{URL: "1http://example.com"}, expected: payloadErr("Link.Validate", "URL '1http://example.com' has no scheme")},
+	{builder: Link{URL: "https:///path"}, expected: payloadErr("Link.Validate", "URL 'https:///path' has no host")},
+	{builder: Link{URL: "https://example.com/a b"}, expected: payloadErr("Link.Validate", "URL 'https://example.com/a b' contains whitespace")},
+}
+
+func Test_URIValidate(test *testing.T) {
+	for i, data := range URIValidate_TestData {
+		if actual := data.builder.Validate(); actual == nil || actual.Error() != data.expected.Error() {
+			test.Errorf(
+				"uri.Test_URIValidate[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+func Test_LinkEncode(test *testing.T) {
+	// The uppercase scheme and host are encoded in the alphanumeric mode,
+	// so the link fits into a smaller symbol than in the byte mode.
+	link := Link{URL: "https://example.com/Getting-Go"}
+	payload, _ := link.Payload()
+	gen := qr.Generator{}
+	byteVersion, _ := gen.TextVersion(link.URL, qr.EccMedium)
+	mixedVersion, _ := gen.MixedTextVersion(payload, qr.EccMedium)
+	if mixedVersion >= byteVersion {
+		test.Errorf("uri.Test_LinkEncode:\n\tactual version -> %d\n is not less than\n\tbyte mode version -> %d", mixedVersion, byteVersion)
+	}
+	encoded, err := link.Encode()
+	if err != nil || len(encoded.GetModules()) != mixedVersion*4+17 {
+		test.Errorf("uri.Test_LinkEncode:\n\tactual size -> %d (err: %v)\n is not equal to\n\texpected -> %d", len(encoded.GetModules()), err, mixedVersion*4+17)
+	}
+}
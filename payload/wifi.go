@@ -0,0 +1,235 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+// Package payload builds structured QR Code payloads, such as Wi-Fi network
+// configurations, and encodes them with suitable error correction levels.
+package payload // import "github.com/YuriyLisovskiy/qrcode/payload"
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/YuriyLisovskiy/qrcode/qr"
+)
+
+// Maximum length of a Wi-Fi network name in bytes.
+const maxSSIDLength = 32
+
+// Characters which are escaped with a backslash in Wi-Fi and MECARD fields.
+const fieldSpecials = "\\;,:\""
+
+// Represents the authentication type of a Wi-Fi network.
+type WiFiSecurity int
+
+const (
+	// Open network without a password.
+	WiFiNone WiFiSecurity = iota
+
+	// WEP protected network.
+	WiFiWEP
+
+	// WPA/WPA2/WPA3 personal network protected by a pre-shared key.
+	WiFiWPA
+
+	// WPA2 enterprise network with EAP authentication.
+	WiFiWPA2EAP
+)
+
+// Values of the T field of Wi-Fi security types.
+var wifiSecurityNames = map[WiFiSecurity]string{
+	WiFiNone:    "nopass",
+	WiFiWEP:     "WEP",
+	WiFiWPA:     "WPA",
+	WiFiWPA2EAP: "WPA2-EAP",
+}
+
+// Supported EAP methods and whether they use a phase 2 authentication.
+var eapMethods = map[string]bool{
+	"PEAP": true,
+	"TTLS": true,
+	"TLS":  false,
+	"PWD":  false,
+	"SIM":  false,
+	"AKA":  false,
+	"AKA'": false,
+}
+
+// Supported phase 2 authentication methods.
+var phase2Methods = map[string]bool{
+	"PAP":      true,
+	"MSCHAP":   true,
+	"MSCHAPV2": true,
+	"GTC":      true,
+}
+
+// Describes a Wi-Fi network configuration, as understood by the camera
+// applications of Android and iOS:
+//
+//	WIFI:T:WPA;S:Office guests;P:secret password;H:true;;
+type WiFi struct {
+	// Name of the network, at most 32 bytes.
+	SSID string
+
+	Security WiFiSecurity
+
+	// Pre-shared key of WEP and WPA networks, password of EAP networks.
+	Password string
+
+	// Indicates a network which does not broadcast its name.
+	Hidden bool
+
+	// EAP method of WPA2-EAP networks: PEAP, TTLS, TLS, PWD, SIM, AKA or AKA'.
+	EapMethod string
+
+	// Identity and anonymous (outer) identity of WPA2-EAP networks.
+	Identity, AnonymousIdentity string
+
+	// Phase 2 authentication of PEAP and TTLS: PAP, MSCHAP, MSCHAPV2 or GTC.
+	Phase2 string
+}
+
+// Returns an error if the configuration cannot be used to join a network.
+func (w WiFi) Validate() error {
+	if w.SSID == "" {
+		return payloadErr("WiFi.Validate", "SSID is empty")
+	}
+	if len(w.SSID) > maxSSIDLength {
+		return payloadErr("WiFi.Validate", fmt.Sprintf("SSID is longer than %d bytes", maxSSIDLength))
+	}
+	if _, ok := wifiSecurityNames[w.Security]; !ok {
+		return payloadErr("WiFi.Validate", fmt.Sprintf("unknown security type %d", w.Security))
+	}
+	if w.Security != WiFiWPA2EAP && (w.EapMethod != "" || w.Identity != "" || w.AnonymousIdentity != "" || w.Phase2 != "") {
+		return payloadErr("WiFi.Validate", "EAP fields are only allowed for WPA2-EAP networks")
+	}
+	switch w.Security {
+	case WiFiNone:
+		if w.Password != "" {
+			return payloadErr("WiFi.Validate", "open network must not have a password")
+		}
+	case WiFiWEP:
+		if !isHex(w.Password) || len(w.Password) != 10 && len(w.Password) != 26 {
+			if len(w.Password) != 5 && len(w.Password) != 13 {
+				return payloadErr("WiFi.Validate", "WEP key must be 5 or 13 characters or 10 or 26 hexadecimal digits")
+			}
+		}
+	case WiFiWPA:
+		if !(len(w.Password) == 64 && isHex(w.Password)) && (len(w.Password) < 8 || len(w.Password) > 63) {
+			return payloadErr("WiFi.Validate", "WPA passphrase must be 8 to 63 characters or 64 hexadecimal digits")
+		}
+		for _, c := range w.Password {
+			if c < ' ' || c > '~' {
+				return payloadErr("WiFi.Validate", "WPA passphrase must consist of printable ASCII characters")
+			}
+		}
+	case WiFiWPA2EAP:
+		phase2, ok := eapMethods[w.EapMethod]
+		if !ok {
+			return payloadErr("WiFi.Validate", fmt.Sprintf("unsupported EAP method '%s'", w.EapMethod))
+		}
+		if w.Phase2 != "" && !phase2 {
+			return payloadErr("WiFi.Validate", fmt.Sprintf("EAP method %s has no phase 2 authentication", w.EapMethod))
+		}
+		if w.Phase2 != "" && !phase2Methods[w.Phase2] {
+			return payloadErr("WiFi.Validate", fmt.Sprintf("unsupported phase 2 authentication '%s'", w.Phase2))
+		}
+	}
+	return nil
+}
+
+// Returns the payload of the configuration. Special characters of all fields
+// are escaped with a backslash, names and passphrases consisting of hexadecimal
+// digits only are quoted, so they are not taken for hexadecimal encoded values.
+// Hexadecimal WEP keys and WPA pre-shared keys are written as is.
+func (w WiFi) Payload() (string, error) {
+	if err := w.Validate(); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "WIFI:T:%s;S:%s;", wifiSecurityNames[w.Security], quoteHex(escapeField(w.SSID)))
+	if w.hexKey() {
+		fmt.Fprintf(&buf, "P:%s;", w.Password)
+	} else if w.Password != "" {
+		fmt.Fprintf(&buf, "P:%s;", quoteHex(escapeField(w.Password)))
+	}
+	if w.Security == WiFiWPA2EAP {
+		fmt.Fprintf(&buf, "E:%s;", w.EapMethod)
+		if w.AnonymousIdentity != "" {
+			fmt.Fprintf(&buf, "A:%s;", escapeField(w.AnonymousIdentity))
+		}
+		if w.Identity != "" {
+			fmt.Fprintf(&buf, "I:%s;", escapeField(w.Identity))
+		}
+		if w.Phase2 != "" {
+			fmt.Fprintf(&buf, "PH2:%s;", w.Phase2)
+		}
+	}
+	if w.Hidden {
+		buf.WriteString("H:true;")
+	}
+	buf.WriteString(";")
+	return buf.String(), nil
+}
+
+// Returns a QR Code of the configuration. Codes of networks are usually printed
+// and put on walls, so at least the medium error correction level is used.
+func (w WiFi) Encode() (qr.Generator, error) {
+	return encodePayload(w.Payload, qr.EccMedium)
+}
+
+// Returns true if the password is a hexadecimal WEP key or WPA pre-shared key
+// rather than a passphrase.
+func (w WiFi) hexKey() bool {
+	switch w.Security {
+	case WiFiWEP:
+		return isHex(w.Password) && (len(w.Password) == 10 || len(w.Password) == 26)
+	case WiFiWPA:
+		return isHex(w.Password) && len(w.Password) == 64
+	}
+	return false
+}
+
+// Encodes the result of the given payload builder at the given error correction level or higher.
+func encodePayload(build func() (string, error), ecl qr.EccLevel) (qr.Generator, error) {
+	text, err := build()
+	if err != nil {
+		return qr.Generator{}, err
+	}
+	gen := qr.Generator{}
+	return gen.EncodeTextAt(text, ecl)
+}
+
+// Escapes special characters of Wi-Fi and MECARD fields with a backslash.
+func escapeField(s string) string {
+	var buf bytes.Buffer
+	for _, c := range s {
+		if strings.ContainsRune(fieldSpecials, c) {
+			buf.WriteByte('\\')
+		}
+		buf.WriteRune(c)
+	}
+	return buf.String()
+}
+
+// Encloses s in double quotes if it is a non-empty string of hexadecimal digits.
+func quoteHex(s string) string {
+	if isHex(s) {
+		return "\"" + s + "\""
+	}
+	return s
+}
+
+// Returns true if s is a non-empty string of hexadecimal digits.
+func isHex(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package payload
+
+import (
+	"strings"
+	"testing"
+)
+
+var WiFiPayload_TestData = []struct {
+	wifi     WiFi
+	expected string
+}{
+	{
+		wifi:     WiFi{SSID: "Office guests", Security: WiFiWPA, Password: "secret password", Hidden: true},
+		expected: "WIFI:T:WPA;S:Office guests;P:secret password;H:true;;",
+	},
+	{
+		wifi:     WiFi{SSID: "Lobby", Security: WiFiNone},
+		expected: "WIFI:T:nopass;S:Lobby;;",
+	},
+	{
+		wifi:     WiFi{SSID: `a\b;c,d:e"f`, Security: WiFiWPA, Password: `p;a:s,s\"`},
+		expected: `WIFI:T:WPA;S:a\\b\;c\,d\:e\"f;P:p\;a\:s\,s\\\";;`,
+	},
+	{
+		wifi:     WiFi{SSID: "CAFE", Security: WiFiWEP, Password: "0123456789"},
+		expected: "WIFI:T:WEP;S:\"CAFE\";P:0123456789;;",
+	},
+	{
+		wifi:     WiFi{SSID: "Lab", Security: WiFiWEP, Password: "0123456789abcdef0123456789"},
+		expected: "WIFI:T:WEP;S:Lab;P:0123456789abcdef0123456789;;",
+	},
+	{
+		wifi:     WiFi{SSID: "Lab", Security: WiFiWEP, Password: "ABCDE"},
+		expected: "WIFI:T:WEP;S:Lab;P:\"ABCDE\";;",
+	},
+	{
+		wifi:     WiFi{SSID: "Home", Security: WiFiWPA, Password: strings.Repeat("0f", 32)},
+		expected: "WIFI:T:WPA;S:Home;P:" + strings.Repeat("0f", 32) + ";;",
+	},
+	{
+		wifi:     WiFi{SSID: "Home", Security: WiFiWPA, Password: "deadbeef1234"},
+		expected: "WIFI:T:WPA;S:Home;P:\"deadbeef1234\";;",
+	},
+	{
+		wifi: WiFi{
+			SSID: "Corp", Security: WiFiWPA2EAP, Password: "pa55word",
+			EapMethod: "PEAP", Identity: "john@corp", AnonymousIdentity: "anon", Phase2: "MSCHAPV2",
+		},
+		expected: "WIFI:T:WPA2-EAP;S:Corp;P:pa55word;E:PEAP;A:anon;I:john@corp;PH2:MSCHAPV2;;",
+	},
+	{
+		wifi:     WiFi{SSID: "Corp", Security: WiFiWPA2EAP, EapMethod: "TLS", Identity: "device", Hidden: true},
+		expected: "WIFI:T:WPA2-EAP;S:Corp;E:TLS;I:device;H:true;;",
+	},
+}
+
+func Test_WiFiPayload(test *testing.T) {
+	for i, data := range WiFiPayload_TestData {
+		actual, err := data.wifi.Payload()
+		if err != nil || actual != data.expected {
+			test.Errorf(
+				"wifi.Test_WiFiPayload[%d]:\n\tactual -> %s (err: %v)\n is not equal to\n\texpected -> %s",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+}
+
+var WiFiValidate_TestData = []struct {
+	wifi     WiFi
+	expected string
+}{
+	{wifi: WiFi{}, expected: "SSID is empty"},
+	{wifi: WiFi{SSID: strings.Repeat("x", 33)}, expected: "SSID is longer than 32 bytes"},
+	{wifi: WiFi{SSID: "x", Security: WiFiSecurity(9)}, expected: "unknown security type 9"},
+	{wifi: WiFi{SSID: "x", Password: "secret"}, expected: "open network must not have a password"},
+	{wifi: WiFi{SSID: "x", Security: WiFiWEP, Password: "1234"}, expected: "WEP key must be 5 or 13 characters or 10 or 26 hexadecimal digits"},
+	{wifi: WiFi{SSID: "x", Security: WiFiWEP, Password: "123456789z"}, expected: "WEP key must be 5 or 13 characters or 10 or 26 hexadecimal digits"},
+	{wifi: WiFi{SSID: "x", Security: WiFiWPA, Password: "short"}, expected: "WPA passphrase must be 8 to 63 characters or 64 hexadecimal digits"},
+	{wifi: WiFi{SSID: "x", Security: WiFiWPA, Password: "pässword"}, expected: "WPA passphrase must consist of printable ASCII characters"},
+	{wifi: WiFi{SSID: "x", Security: WiFiWPA, Password: "password", Identity: "me"}, expected: "EAP fields are only allowed for WPA2-EAP networks"},
+	{wifi: WiFi{SSID: "x", Security: WiFiWPA2EAP, EapMethod: "LEAP"}, expected: "unsupported EAP method 'LEAP'"},
+	{wifi: WiFi{SSID: "x", Security: WiFiWPA2EAP, EapMethod: "TLS", Phase2: "PAP"}, expected: "EAP method TLS has no phase 2 authentication"},
+	{wifi: WiFi{SSID: "x", Security: WiFiWPA2EAP, EapMethod: "TTLS", Phase2: "CHAP"}, expected: "unsupported phase 2 authentication 'CHAP'"},
+}
+
+func Test_WiFiValidate(test *testing.T) {
+	for i, data := range WiFiValidate_TestData {
+		expected := payloadErr("WiFi.Validate", data.expected)
+		if actual := data.wifi.Validate(); actual == nil || actual.Error() != expected.Error() {
+			test.Errorf(
+				"wifi.Test_WiFiValidate[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, expected,
+			)
+		}
+	}
+	valid := []WiFi{
+		{SSID: "x", Security: WiFiWEP, Password: "abcde"},
+		{SSID: "x", Security: WiFiWEP, Password: "0123456789abcdef0123456789"},
+		{SSID: "x", Security: WiFiWPA, Password: strings.Repeat("a", 63)},
+		{SSID: "x", Security: WiFiWPA, Password: strings.Repeat("f", 64)},
+	}
+	for i, wifi := range valid {
+		if err := wifi.Validate(); err != nil {
+			test.Errorf("wifi.Test_WiFiValidate:\n\tvalid configuration %d is rejected -> %v", i, err)
+		}
+	}
+}
+
+func Test_WiFiEncode(test *testing.T) {
+	wifi := WiFi{SSID: "Office guests", Security: WiFiWPA, Password: "secret password"}
+	gen, err := wifi.Encode()
+	if err != nil {
+		test.Fatalf("wifi.Test_WiFiEncode:\n\tunexpected error -> %s", err)
+	}
+	json, _ := gen.ToJson()
+	if strings.Contains(json, "\"ecc\":\"L\"") {
+		test.Errorf("wifi.Test_WiFiEncode:\n\tlow error correction level is used -> %s", json)
+	}
+	if _, err := (WiFi{}).Encode(); err == nil {
+		test.Errorf("wifi.Test_WiFiEncode:\n\tinvalid configuration is encoded")
+	}
+}
+
+func Test_escapeField(test *testing.T) {
+	if actual, expected := escapeField(`a\b;c,d:e"f`), `a\\b\;c\,d\:e\"f`; actual != expected {
+		test.Errorf("wifi.Test_escapeField:\n\tactual -> %s\n is not equal to\n\texpected -> %s", actual, expected)
+	}
+}
@@ -0,0 +1,101 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"strings"
+	"testing"
+)
+
+var glyphPixel_TestData = []struct {
+	c        rune
+	expected []string
+}{
+	{
+		c: 'A',
+		expected: []string{
+			" ### ",
+			"#   #",
+			"#   #",
+			"#   #",
+			"#####",
+			"#   #",
+			"#   #",
+		},
+	},
+	{
+		c: '1',
+		expected: []string{
+			"  #  ",
+			" ##  ",
+			"  #  ",
+			"  #  ",
+			"  #  ",
+			"  #  ",
+			" ### ",
+		},
+	},
+	{
+		c:        'é',
+		expected: []string{"     ", "     ", "     ", "     ", "     ", "     ", "     "},
+	},
+}
+
+func Test_glyphPixel(test *testing.T) {
+	for i, data := range glyphPixel_TestData {
+		var rows []string
+		for y := 0; y < glyphHeight; y++ {
+			row := ""
+			for x := 0; x < glyphWidth; x++ {
+				if glyphPixel(data.c, x, y) {
+					row += "#"
+				} else {
+					row += " "
+				}
+			}
+			rows = append(rows, row)
+		}
+		if actual, expected := strings.Join(rows, "\n"), strings.Join(data.expected, "\n"); actual != expected {
+			test.Errorf(
+				"bitmap_font.Test_glyphPixel[%d]:\n\tactual glyph ->\n%s\n is not equal to\n\texpected glyph ->\n%s",
+				i, actual, expected,
+			)
+		}
+	}
+}
+
+func Test_bitmapFont(test *testing.T) {
+	for i, glyph := range bitmapFont {
+		for x, column := range glyph {
+			if column>>glyphHeight != 0 {
+				test.Errorf("bitmap_font.Test_bitmapFont:\n\tglyph %q column %d is higher than %d pixels",
+					rune(firstGlyph+i), x, glyphHeight)
+			}
+		}
+	}
+	if glyphPixel('A', -1, 0) || glyphPixel('A', glyphWidth, 0) || glyphPixel('A', 0, glyphHeight) {
+		test.Errorf("bitmap_font.Test_bitmapFont:\n\tpixels outside of a glyph are set")
+	}
+}
+
+var textWidth_TestData = []struct {
+	text     string
+	expected int
+}{
+	{text: "", expected: 0},
+	{text: "A", expected: 5},
+	{text: "Scan to pay", expected: 65},
+}
+
+func Test_textWidth(test *testing.T) {
+	for i, data := range textWidth_TestData {
+		if actual := textWidth(data.text); actual != data.expected {
+			test.Errorf(
+				"bitmap_font.Test_textWidth[%d]:\n\tactual -> %d\n is not equal to\n\texpected -> %d",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
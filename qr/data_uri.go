@@ -0,0 +1,87 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"regexp"
+	"strings"
+)
+
+// MIME types of image formats.
+var imageFormatMimeTypes = map[ImageFormat]string{
+	FormatPNG:  "image/png",
+	FormatGIF:  "image/gif",
+	FormatJPEG: "image/jpeg",
+	FormatBMP:  "image/bmp",
+	FormatPBM:  "image/x-portable-bitmap",
+	FormatPGM:  "image/x-portable-graymap",
+}
+
+// Matches the XML declaration and the document type declaration of an SVG document.
+var svgPrologRegexp = regexp.MustCompile(`^\s*(<\?xml[^>]*\?>\s*)?(<!DOCTYPE[^>]*>\s*)?`)
+
+// Matches white space between tags of an SVG document.
+var svgTagSpaceRegexp = regexp.MustCompile(`>\s+<`)
+
+// Returns generated QR Code as a base64 encoded PNG data URI, e.g. for the src
+// attribute of an <img> element. Parameters are the same as of DrawImage.
+func (gen *Generator) ToPngDataUri(margin, pictureSize uint) (string, error) {
+	img, err := gen.toImage("ToPngDataUri", margin, pictureSize)
+	if err != nil {
+		return "", err
+	}
+	return ImageDataUri(img, FormatPNG)
+}
+
+// Returns svg of generated QR Code as a URL-encoded data URI.
+func (gen *Generator) ToSvgDataUri(border int) (string, error) {
+	svg, err := gen.ToSvg(border)
+	if err != nil {
+		return "", err
+	}
+	return SvgDataUri(svg), nil
+}
+
+// Returns img encoded in the given format as a base64 encoded data URI.
+// Use it with images of other renderers, e.g. StyledImage or FramedImage.
+func ImageDataUri(img image.Image, format ImageFormat) (string, error) {
+	mimeType, ok := imageFormatMimeTypes[format]
+	if !ok {
+		return "", generatorErr("ImageDataUri", fmt.Sprintf("unsupported image format %d", format))
+	}
+	var buf bytes.Buffer
+	if err := encodeImage(&buf, img, format); err != nil {
+		return "", err
+	}
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// Returns the given SVG document as a data URI. URL encoding is used instead
+// of base64, which is about a third larger: the XML and document type
+// declarations and white space between tags are dropped, double quotes
+// are replaced by single ones where possible and only characters which
+// are not allowed in URIs are percent-encoded.
+func SvgDataUri(svg string) string {
+	svg = svgPrologRegexp.ReplaceAllString(svg, "")
+	svg = strings.TrimSpace(svgTagSpaceRegexp.ReplaceAllString(svg, "><"))
+	if !strings.Contains(svg, "'") {
+		svg = strings.Replace(svg, "\"", "'", -1)
+	}
+	var buf bytes.Buffer
+	buf.WriteString("data:image/svg+xml,")
+	for i := 0; i < len(svg); i++ {
+		c := svg[i]
+		if c <= ' ' || c >= 0x7f || strings.IndexByte("\"%#<>[\\]^`{|}", c) >= 0 {
+			fmt.Fprintf(&buf, "%%%02X", c)
+		} else {
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,118 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+var SvgDataUri_TestData = []struct {
+	svg      string
+	expected string
+}{
+	{
+		svg:      "<?xml version=\"1.0\"?>\n<!DOCTYPE svg>\n<svg viewBox=\"0 0 1 1\">\n\t<path d=\"M0,0h1v1h-1z\" fill=\"#000000\"/>\n</svg>",
+		expected: "data:image/svg+xml,%3Csvg%20viewBox='0%200%201%201'%3E%3Cpath%20d='M0,0h1v1h-1z'%20fill='%23000000'/%3E%3C/svg%3E",
+	},
+	{
+		svg:      "<svg><text font-family=\"'a'\">100% {x}</text></svg>",
+		expected: "data:image/svg+xml,%3Csvg%3E%3Ctext%20font-family=%22'a'%22%3E100%25%20%7Bx%7D%3C/text%3E%3C/svg%3E",
+	},
+	{
+		svg:      "<svg>é</svg>",
+		expected: "data:image/svg+xml,%3Csvg%3E%C3%A9%3C/svg%3E",
+	},
+}
+
+func Test_SvgDataUri(test *testing.T) {
+	for i, data := range SvgDataUri_TestData {
+		if actual := SvgDataUri(data.svg); actual != data.expected {
+			test.Errorf(
+				"data_uri.Test_SvgDataUri[%d]:\n\tactual -> %s\n is not equal to\n\texpected -> %s",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+func Test_ToSvgDataUri(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	uri, err := gen.ToSvgDataUri(4)
+	if err != nil {
+		test.Fatalf("data_uri.Test_ToSvgDataUri:\n\tunexpected error -> %s", err)
+	}
+	// QueryUnescape turns '+' into a space, so a literal '+' is escaped first.
+	svg, err := url.QueryUnescape(strings.Replace(strings.TrimPrefix(uri, "data:image/svg+xml,"), "+", "%2B", -1))
+	if err != nil {
+		test.Fatalf("data_uri.Test_ToSvgDataUri:\n\tunescaping failed -> %s", err)
+	}
+	expected, _ := gen.ToSvg(4)
+	expected = strings.Replace(expected[strings.Index(expected, "<svg"):], "\"", "'", -1)
+	expected = strings.Replace(strings.Replace(expected, ">\n\t<", "><", -1), ">\n<", "><", -1)
+	if svg != expected {
+		test.Errorf("data_uri.Test_ToSvgDataUri:\n\tactual -> %s\n is not equal to\n\texpected -> %s", svg, expected)
+	}
+	if _, err := gen.ToSvgDataUri(-1); err == nil {
+		test.Errorf("data_uri.Test_ToSvgDataUri:\n\tnegative border is accepted")
+	}
+}
+
+func Test_ToPngDataUri(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	uri, err := gen.ToPngDataUri(4, 58)
+	if err != nil {
+		test.Fatalf("data_uri.Test_ToPngDataUri:\n\tunexpected error -> %s", err)
+	}
+	if !strings.HasPrefix(uri, "data:image/png;base64,") {
+		test.Fatalf("data_uri.Test_ToPngDataUri:\n\tunexpected prefix -> %s", uri)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(uri, "data:image/png;base64,"))
+	if err != nil {
+		test.Fatalf("data_uri.Test_ToPngDataUri:\n\tbase64 decoding failed -> %s", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		test.Fatalf("data_uri.Test_ToPngDataUri:\n\tpng decoding failed -> %s", err)
+	}
+	if actual, err := decodeModules(sampleModules(img, gen.size, 4, 2)); err != nil || actual != "HELLO WORLD" {
+		test.Errorf("data_uri.Test_ToPngDataUri:\n\tactual text -> %q (err: %v)", actual, err)
+	}
+	if _, err := gen.ToPngDataUri(4, 10); err == nil {
+		test.Errorf("data_uri.Test_ToPngDataUri:\n\ttoo small picture is accepted")
+	}
+}
+
+var ImageDataUri_TestData = []struct {
+	format   ImageFormat
+	expected string
+}{
+	{format: FormatGIF, expected: "data:image/gif;base64,R0lG"},
+	{format: FormatBMP, expected: "data:image/bmp;base64,Qk"},
+	{format: FormatPBM, expected: "data:image/x-portable-bitmap;base64,UDQK"},
+}
+
+func Test_ImageDataUri(test *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 2, 2))
+	for i, data := range ImageDataUri_TestData {
+		actual, err := ImageDataUri(img, data.format)
+		if err != nil || !strings.HasPrefix(actual, data.expected) {
+			test.Errorf(
+				"data_uri.Test_ImageDataUri[%d]:\n\tactual -> %s (err: %v)\n does not start with\n\texpected -> %s",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+	if _, err := ImageDataUri(img, ImageFormat(42)); err == nil {
+		test.Errorf("data_uri.Test_ImageDataUri:\n\tunsupported format is accepted")
+	}
+}
@@ -0,0 +1,283 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+)
+
+const (
+	// Default module size of debug images in pixels.
+	defaultDebugModuleSize = 16
+
+	// Period of the hatching of error correction codewords in pixels.
+	debugHatchPeriod = 6
+)
+
+// Colors of function module roles in debug renderers.
+var debugRoleColors = map[ModuleRole]color.RGBA{
+	RoleFinder:      {R: 0x44, G: 0x44, B: 0x44, A: 0xff},
+	RoleSeparator:   {R: 0xbb, G: 0xbb, B: 0xbb, A: 0xff},
+	RoleTiming:      {R: 0xf0, G: 0x8c, B: 0x00, A: 0xff},
+	RoleAlignment:   {R: 0x7b, G: 0x1f, B: 0xa2, A: 0xff},
+	RoleFormatInfo:  {R: 0xd3, G: 0x2f, B: 0x2f, A: 0xff},
+	RoleVersionInfo: {R: 0xc2, G: 0x18, B: 0x5b, A: 0xff},
+	RoleDarkModule:  {R: 0x5d, G: 0x40, B: 0x37, A: 0xff},
+	RoleRemainder:   {R: 0x9e, G: 0xb0, B: 0x9e, A: 0xff},
+}
+
+// Describes how the structure of a QR Code is visualized by debug renderers.
+type DebugOptions struct {
+	// Width of the quiet zone measured in modules.
+	Margin uint
+
+	// Side of a module in pixels of raster images, 16 if zero.
+	ModuleSize uint
+
+	// Draws the index of every codeword in the interleaved sequence.
+	Labels bool
+}
+
+// Point measured in modules.
+type debugPoint struct {
+	X, Y float64
+}
+
+// Edge of a module between the given points, measured in modules.
+type debugEdge struct {
+	from, to image.Point
+}
+
+// Returns generated QR Code as an image visualizing the structure of the
+// symbol: function patterns, format and version information are drawn in
+// their own colors, codewords of every error correction block take a hue of
+// their own, error correction codewords are hatched and every codeword is
+// outlined. Black modules are drawn in saturated colors, white ones in pale colors.
+func (gen *Generator) DebugImage(opts DebugOptions) image.Image {
+	return gen.debugImage(opts)
+}
+
+// Writes the image returned by DebugImage to w in the given format.
+func (gen *Generator) EncodeDebugImage(w io.Writer, format ImageFormat, opts DebugOptions) error {
+	return encodeImage(w, gen.debugImage(opts), format)
+}
+
+// Returns svg string visualizing the structure of generated QR Code
+// the same way as DebugImage does.
+func (gen *Generator) ToDebugSvg(opts DebugOptions) string {
+	roles := gen.ModuleRoles()
+	border := float64(opts.Margin)
+	side := gen.size + int(opts.Margin)*2
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+		"<!DOCTYPE svg PUBLIC \"-//W3C//DTD SVG 1.1//EN\" \"http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd\">\n"+
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" version=\"1.1\" viewBox=\"0 0 %d %d\" stroke=\"none\">\n"+
+		"\t<defs>\n"+
+		"\t\t<pattern id=\"hatch\" width=\"0.25\" height=\"0.25\" patternUnits=\"userSpaceOnUse\" patternTransform=\"rotate(45)\">\n"+
+		"\t\t\t<rect width=\"0.08\" height=\"0.25\" fill=\"#000000\" fill-opacity=\"0.4\"/>\n"+
+		"\t\t</pattern>\n"+
+		"\t</defs>\n"+
+		"\t<rect width=\"100%%\" height=\"100%%\" fill=\"#FFFFFF\"/>\n", side, side)
+	var hatch bytes.Buffer
+	for y, row := range roles {
+		for x, info := range row {
+			fmt.Fprintf(&buf, "\t<rect x=\"%d\" y=\"%d\" width=\"1\" height=\"1\" fill=\"%s\"/>\n",
+				x+int(opts.Margin), y+int(opts.Margin), svgColor(gen.debugColor(info, gen.module(x, y))))
+			if info.Role == RoleEcc {
+				fmt.Fprintf(&hatch, "M%d,%dh1v1h-1z", x+int(opts.Margin), y+int(opts.Margin))
+			}
+		}
+	}
+	if hatch.Len() > 0 {
+		fmt.Fprintf(&buf, "\t<path d=\"%s\" fill=\"url(#hatch)\"/>\n", hatch.String())
+	}
+	buf.WriteString("\t<path d=\"")
+	for _, edge := range debugEdges(roles) {
+		fmt.Fprintf(&buf, "M%s,%sL%s,%s", fmtFloat(float64(edge.from.X)+border), fmtFloat(float64(edge.from.Y)+border),
+			fmtFloat(float64(edge.to.X)+border), fmtFloat(float64(edge.to.Y)+border))
+	}
+	buf.WriteString("\" fill=\"none\" stroke=\"#000000\" stroke-width=\"0.1\"/>\n")
+	if opts.Labels {
+		for i, center := range debugCenters(roles) {
+			fmt.Fprintf(&buf, "\t<text x=\"%s\" y=\"%s\" font-family=\"monospace\" font-size=\"0.6\" "+
+				"text-anchor=\"middle\" dominant-baseline=\"central\">%d</text>\n",
+				fmtFloat(center.X+border), fmtFloat(center.Y+border), i)
+		}
+	}
+	buf.WriteString("</svg>")
+	return buf.String()
+}
+
+// Helper method for debug raster renderers.
+func (gen *Generator) debugImage(opts DebugOptions) *image.RGBA {
+	moduleSize := int(opts.ModuleSize)
+	if moduleSize == 0 {
+		moduleSize = defaultDebugModuleSize
+	}
+	margin := int(opts.Margin)
+	roles := gen.ModuleRoles()
+	side := (gen.size + margin*2) * moduleSize
+	img := image.NewRGBA(image.Rect(0, 0, side, side))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for y, row := range roles {
+		for x, info := range row {
+			c := gen.debugColor(info, gen.module(x, y))
+			hatched := blendColors(color.RGBA{A: 0xff}, c, 0.4)
+			r := modulePixels(image.Rect(x, y, x+1, y+1), margin, moduleSize)
+			for py := r.Min.Y; py < r.Max.Y; py++ {
+				for px := r.Min.X; px < r.Max.X; px++ {
+					if info.Role == RoleEcc && (px+py)%debugHatchPeriod < debugHatchPeriod/3 {
+						img.SetRGBA(px, py, hatched)
+					} else {
+						img.SetRGBA(px, py, c)
+					}
+				}
+			}
+		}
+	}
+	line := moduleSize / 8
+	if line < 1 {
+		line = 1
+	}
+	black := color.RGBA{A: 0xff}
+	for _, edge := range debugEdges(roles) {
+		r := image.Rect(
+			(edge.from.X+margin)*moduleSize-line/2, (edge.from.Y+margin)*moduleSize-line/2,
+			(edge.to.X+margin)*moduleSize+(line+1)/2, (edge.to.Y+margin)*moduleSize+(line+1)/2,
+		)
+		for py := r.Min.Y; py < r.Max.Y; py++ {
+			for px := r.Min.X; px < r.Max.X; px++ {
+				img.SetRGBA(px, py, black)
+			}
+		}
+	}
+	if opts.Labels {
+		scale := moduleSize / 12
+		if scale < 1 {
+			scale = 1
+		}
+		for i, center := range debugCenters(roles) {
+			label := []rune(strconv.Itoa(i))
+			left := int(math.Floor((center.X+float64(margin))*float64(moduleSize))) - textWidth(string(label))*scale/2
+			top := int(math.Floor((center.Y+float64(margin))*float64(moduleSize))) - glyphHeight*scale/2
+			for py := 0; py < glyphHeight*scale; py++ {
+				for px := 0; px < textWidth(string(label))*scale; px++ {
+					gx, gy := px/scale, py/scale
+					if glyphPixel(label[gx/glyphAdvance], gx%glyphAdvance, gy) {
+						img.SetRGBA(left+px, top+py, black)
+					}
+				}
+			}
+		}
+	}
+	return img
+}
+
+// Returns the color of a module with the given role and color in debug renderers.
+func (gen *Generator) debugColor(info ModuleInfo, dark bool) color.RGBA {
+	c, ok := debugRoleColors[info.Role]
+	if !ok {
+		numBlocks := numErrorCorrectionBlocks[gen.errorCorrectionLevel][gen.version]
+		c = hueColor(float64(info.Block) / float64(numBlocks) * 360)
+	}
+	if dark {
+		return c
+	}
+	return blendColors(c, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, 0.3)
+}
+
+// Returns a saturated color of the given hue in degrees.
+func hueColor(hue float64) color.RGBA {
+	const saturation, value = 0.75, 0.85
+	h := math.Mod(hue, 360) / 60
+	chroma := value * saturation
+	x := chroma * (1 - math.Abs(math.Mod(h, 2)-1))
+	var r, g, b float64
+	switch int(h) {
+	case 0:
+		r, g = chroma, x
+	case 1:
+		r, g = x, chroma
+	case 2:
+		g, b = chroma, x
+	case 3:
+		g, b = x, chroma
+	case 4:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+	m := value - chroma
+	channel := func(v float64) uint8 {
+		return uint8(math.Floor((v+m)*255 + 0.5))
+	}
+	return color.RGBA{R: channel(r), G: channel(g), B: channel(b), A: 0xff}
+}
+
+// Returns the edges between modules of different codewords and between
+// codeword modules and other modules, measured in modules.
+func debugEdges(roles [][]ModuleInfo) []debugEdge {
+	codeword := func(x, y int) int {
+		if y < 0 || y >= len(roles) || x < 0 || x >= len(roles[y]) {
+			return -1
+		}
+		return roles[y][x].Codeword
+	}
+	var edges []debugEdge
+	for y, row := range roles {
+		for x, info := range row {
+			if info.Codeword < 0 {
+				continue
+			}
+			if codeword(x-1, y) < 0 {
+				edges = append(edges, debugEdge{image.Pt(x, y), image.Pt(x, y+1)})
+			}
+			if codeword(x, y-1) < 0 {
+				edges = append(edges, debugEdge{image.Pt(x, y), image.Pt(x+1, y)})
+			}
+			if codeword(x+1, y) != info.Codeword {
+				edges = append(edges, debugEdge{image.Pt(x+1, y), image.Pt(x+1, y+1)})
+			}
+			if codeword(x, y+1) != info.Codeword {
+				edges = append(edges, debugEdge{image.Pt(x, y+1), image.Pt(x+1, y+1)})
+			}
+		}
+	}
+	return edges
+}
+
+// Returns the center of every codeword indexed by its position in the
+// interleaved sequence, as the average center of its modules.
+func debugCenters(roles [][]ModuleInfo) []debugPoint {
+	var centers []debugPoint
+	var counts []int
+	for y, row := range roles {
+		for x, info := range row {
+			if info.Codeword < 0 {
+				continue
+			}
+			for len(centers) <= info.Codeword {
+				centers = append(centers, debugPoint{})
+				counts = append(counts, 0)
+			}
+			centers[info.Codeword].X += float64(x) + 0.5
+			centers[info.Codeword].Y += float64(y) + 0.5
+			counts[info.Codeword]++
+		}
+	}
+	for i := range centers {
+		centers[i].X /= float64(counts[i])
+		centers[i].Y /= float64(counts[i])
+	}
+	return centers
+}
@@ -0,0 +1,154 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Returns module roles with the given codeword indices, -1 marks function modules.
+func testDebugRoles(codewords [][]int) [][]ModuleInfo {
+	roles := make([][]ModuleInfo, len(codewords))
+	for y, row := range codewords {
+		for _, codeword := range row {
+			info := ModuleInfo{Role: RoleFinder, Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}
+			if codeword >= 0 {
+				info = ModuleInfo{Role: RoleData, Codeword: codeword, Block: 0, BlockCodeword: codeword, Bit: 7}
+			}
+			roles[y] = append(roles[y], info)
+		}
+	}
+	return roles
+}
+
+func Test_debugEdges(test *testing.T) {
+	roles := testDebugRoles([][]int{{0, 0}, {1, -1}})
+	expected := []debugEdge{
+		{image.Pt(0, 0), image.Pt(0, 1)},
+		{image.Pt(0, 0), image.Pt(1, 0)},
+		{image.Pt(0, 1), image.Pt(1, 1)},
+		{image.Pt(1, 0), image.Pt(2, 0)},
+		{image.Pt(2, 0), image.Pt(2, 1)},
+		{image.Pt(1, 1), image.Pt(2, 1)},
+		{image.Pt(0, 1), image.Pt(0, 2)},
+		{image.Pt(1, 1), image.Pt(1, 2)},
+		{image.Pt(0, 2), image.Pt(1, 2)},
+	}
+	if actual := debugEdges(roles); !reflect.DeepEqual(actual, expected) {
+		test.Errorf("debug_renderer.Test_debugEdges:\n\tactual -> %v\n is not equal to\n\texpected -> %v", actual, expected)
+	}
+}
+
+func Test_debugCenters(test *testing.T) {
+	roles := testDebugRoles([][]int{{0, 0}, {1, -1}})
+	expected := []debugPoint{{X: 1, Y: 0.5}, {X: 0.5, Y: 1.5}}
+	if actual := debugCenters(roles); !reflect.DeepEqual(actual, expected) {
+		test.Errorf("debug_renderer.Test_debugCenters:\n\tactual -> %v\n is not equal to\n\texpected -> %v", actual, expected)
+	}
+}
+
+var hueColor_TestData = []struct {
+	hue      float64
+	expected color.RGBA
+}{
+	{hue: 0, expected: color.RGBA{R: 217, G: 54, B: 54, A: 255}},
+	{hue: 120, expected: color.RGBA{R: 54, G: 217, B: 54, A: 255}},
+	{hue: 240, expected: color.RGBA{R: 54, G: 54, B: 217, A: 255}},
+	{hue: 360, expected: color.RGBA{R: 217, G: 54, B: 54, A: 255}},
+}
+
+func Test_hueColor(test *testing.T) {
+	for i, data := range hueColor_TestData {
+		if actual := hueColor(data.hue); actual != data.expected {
+			test.Errorf(
+				"debug_renderer.Test_hueColor[%d]:\n\tactual -> %v\n is not equal to\n\texpected -> %v",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+func Test_DebugImage(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	var buf bytes.Buffer
+	if err := gen.EncodeDebugImage(&buf, FormatPNG, DebugOptions{Margin: 1}); err != nil {
+		test.Fatalf("debug_renderer.Test_DebugImage:\n\tunexpected error -> %s", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		test.Fatalf("debug_renderer.Test_DebugImage:\n\tdecoding failed -> %s", err)
+	}
+	if actual, expected := img.Bounds(), image.Rect(0, 0, 368, 368); actual != expected {
+		test.Errorf("debug_renderer.Test_DebugImage:\n\tactual bounds -> %v\n is not equal to\n\texpected -> %v", actual, expected)
+	}
+	roles := gen.ModuleRoles()
+	// Pixel (24, 24) is in the center of the upper left finder module.
+	if actual := toRGBA(img.At(24, 24)); actual != debugRoleColors[RoleFinder] {
+		test.Errorf("debug_renderer.Test_DebugImage:\n\tactual finder color -> %v", actual)
+	}
+	// Bottom right module is the first bit of codeword 0 of block 0.
+	if actual, expected := toRGBA(img.At(344, 341)), gen.debugColor(roles[20][20], gen.module(20, 20)); actual != expected {
+		test.Errorf("debug_renderer.Test_DebugImage:\n\tactual data color -> %v\n is not equal to\n\texpected -> %v", actual, expected)
+	}
+	// Codeword 0 is outlined from the right.
+	if actual := toRGBA(img.At(351, 344)); actual != (color.RGBA{A: 255}) {
+		test.Errorf("debug_renderer.Test_DebugImage:\n\tactual outline color -> %v", actual)
+	}
+	hatched, plain := false, false
+	for y, row := range roles {
+		for x, info := range row {
+			if info.Role != RoleEcc {
+				continue
+			}
+			r := modulePixels(image.Rect(x, y, x+1, y+1), 1, 16).Inset(3)
+			for py := r.Min.Y; py < r.Max.Y; py++ {
+				for px := r.Min.X; px < r.Max.X; px++ {
+					c := toRGBA(img.At(px, py))
+					hatched = hatched || c != gen.debugColor(info, gen.module(x, y))
+					plain = plain || c == gen.debugColor(info, gen.module(x, y))
+				}
+			}
+		}
+	}
+	if !hatched || !plain {
+		test.Errorf("debug_renderer.Test_DebugImage:\n\terror correction codewords are not hatched")
+	}
+	labelled := gen.DebugImage(DebugOptions{Margin: 1, Labels: true})
+	if bytes.Equal(labelled.(*image.RGBA).Pix, gen.DebugImage(DebugOptions{Margin: 1}).(*image.RGBA).Pix) {
+		test.Errorf("debug_renderer.Test_DebugImage:\n\tlabels are not drawn")
+	}
+}
+
+func Test_ToDebugSvg(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	svg := gen.ToDebugSvg(DebugOptions{Margin: 2, Labels: true})
+	for _, expected := range []string{
+		"viewBox=\"0 0 25 25\"",
+		"<pattern id=\"hatch\"",
+		"fill=\"url(#hatch)\"",
+		"\t<rect x=\"2\" y=\"2\" width=\"1\" height=\"1\" fill=\"#444444\"/>\n",
+		"stroke=\"#000000\"",
+		">0</text>",
+		">25</text>",
+	} {
+		if !strings.Contains(svg, expected) {
+			test.Errorf("debug_renderer.Test_ToDebugSvg:\n\tsvg does not contain\n\texpected -> %s", expected)
+		}
+	}
+	if actual := strings.Count(svg, "width=\"1\" height=\"1\""); actual != 21*21 {
+		test.Errorf("debug_renderer.Test_ToDebugSvg:\n\tactual module count -> %d\n is not equal to\n\texpected -> %d", actual, 21*21)
+	}
+	if strings.Contains(gen.ToDebugSvg(DebugOptions{}), "<text") {
+		test.Errorf("debug_renderer.Test_ToDebugSvg:\n\tlabels are drawn without being requested")
+	}
+}
@@ -0,0 +1,256 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"testing"
+)
+
+// Minimal QR Code decoder used to verify round trips of renderers in tests.
+// It expects an undamaged, axis-aligned symbol with a known module grid and
+// verifies error correction codewords instead of correcting errors.
+
+// Samples every module of a symbol with the given size like a scanner does:
+// a module is dark if most pixels of its central third are dark. Renderers
+// which keep only the center of a module solid have to cover that area.
+func sampleModules(img image.Image, size, margin, moduleSize int) [][]bool {
+	window := moduleSize / 3
+	if window < 1 {
+		window = 1
+	}
+	start := (moduleSize - window) / 2
+	modules := make([][]bool, size)
+	for y := range modules {
+		modules[y] = make([]bool, size)
+		for x := range modules[y] {
+			dark := 0
+			for py := 0; py < window; py++ {
+				for px := 0; px < window; px++ {
+					ix := (x+margin)*moduleSize + start + px
+					iy := (y+margin)*moduleSize + start + py
+					if isDarkColor(img.At(img.Bounds().Min.X+ix, img.Bounds().Min.Y+iy)) {
+						dark++
+					}
+				}
+			}
+			modules[y][x] = dark*2 > window*window
+		}
+	}
+	return modules
+}
+
+// Returns the number of set bits of n.
+func onesCount(n int) int {
+	count := 0
+	for ; n != 0; n &= n - 1 {
+		count++
+	}
+	return count
+}
+
+// Returns the format word (with error correction and mask applied)
+// for the given error correction level and mask.
+func testFormatWord(ecl eccType, mask int) int {
+	data := Generator{}.getFormatBits(ecl)<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// Decodes the text of the given module matrix.
+func decodeModules(modules [][]bool) (string, error) {
+	size := len(modules)
+	if size < 21 || size > 177 || (size-17)%4 != 0 {
+		return "", errors.New("invalid symbol size")
+	}
+	format := 0
+	for i := 0; i <= 5; i++ {
+		if modules[i][8] {
+			format |= 1 << uint(i)
+		}
+	}
+	for i, p := range []image.Point{{8, 7}, {8, 8}, {7, 8}} {
+		if modules[p.Y][p.X] {
+			format |= 1 << uint(6+i)
+		}
+	}
+	for i := 9; i < 15; i++ {
+		if modules[8][14-i] {
+			format |= 1 << uint(i)
+		}
+	}
+	ecl, mask, best := eccLOW, 0, 16
+	for _, e := range []eccType{eccLOW, eccMEDIUM, eccQUARTILE, eccHIGH} {
+		for m := 0; m < 8; m++ {
+			if distance := onesCount(testFormatWord(e, m) ^ format); distance < best {
+				ecl, mask, best = e, m, distance
+			}
+		}
+	}
+	if best > 3 {
+		return "", errors.New("unreadable format information")
+	}
+
+	gen := Generator{version: (size - 17) / 4, size: size, errorCorrectionLevel: ecl}
+	gen.modules = make([][]bool, size)
+	gen.isFunction = make([][]bool, size)
+	for y := range gen.modules {
+		gen.modules[y] = make([]bool, size)
+		gen.isFunction[y] = make([]bool, size)
+	}
+	gen.drawFunctionPatterns()
+	for y := range modules {
+		copy(gen.modules[y], modules[y])
+	}
+	gen.applyMask(mask)
+
+	order := gen.dataModuleOrder()
+	raw := make([]uint8, gen.getNumRawDataModules(gen.version)/8)
+	for i := range raw {
+		for j := 0; j < 8; j++ {
+			p := order[i*8+j]
+			if gen.modules[p.Y][p.X] {
+				raw[i] |= 0x80 >> uint(j)
+			}
+		}
+	}
+	numBlocks := numErrorCorrectionBlocks[ecl][gen.version]
+	blocks := make([][]uint8, numBlocks)
+	eccBlocks := make([][]uint8, numBlocks)
+	for i, source := range gen.codewordSources() {
+		if source.isEcc {
+			eccBlocks[source.block] = append(eccBlocks[source.block], raw[i])
+		} else {
+			blocks[source.block] = append(blocks[source.block], raw[i])
+		}
+	}
+	rs, err := newReedSolomonGenerator(eccCodewordsPerBlock[ecl][gen.version])
+	if err != nil {
+		return "", err
+	}
+	var data bitBuffer
+	for i, block := range blocks {
+		if !bytes.Equal(rs.getRemainder(&block), eccBlocks[i]) {
+			return "", fmt.Errorf("block %d is damaged", i)
+		}
+		for _, b := range block {
+			data, _ = data.appendBits(uint32(b), 8)
+		}
+	}
+	return decodeSegments(data, gen.version)
+}
+
+// Decodes text from the data bit stream of a symbol of the given version.
+func decodeSegments(data bitBuffer, version int) (string, error) {
+	pos := 0
+	read := func(n int) (int, error) {
+		if pos+n > len(data) {
+			return 0, errors.New("unexpected end of data")
+		}
+		result := 0
+		for i := 0; i < n; i++ {
+			result <<= 1
+			if data[pos+i] {
+				result |= 1
+			}
+		}
+		pos += n
+		return result, nil
+	}
+	var text bytes.Buffer
+	for len(data)-pos >= 4 {
+		modeBits, _ := read(4)
+		var mode modeType
+		switch modeBits {
+		case 0:
+			return text.String(), nil
+		case isNUMERIC.getModeBits():
+			mode = isNUMERIC
+		case isALPHANUMERIC.getModeBits():
+			mode = isALPHANUMERIC
+		case isBYTE.getModeBits():
+			mode = isBYTE
+		case isECI.getModeBits():
+			if _, err := read(8); err != nil {
+				return "", err
+			}
+			continue
+		default:
+			return "", fmt.Errorf("unsupported mode %d", modeBits)
+		}
+		countBits, _ := mode.numCharCountBits(version)
+		count, err := read(countBits)
+		if err != nil {
+			return "", err
+		}
+		for count > 0 {
+			switch mode {
+			case isNUMERIC:
+				digits := 3
+				if count < 3 {
+					digits = count
+				}
+				value, err := read(digits*3 + 1)
+				if err != nil {
+					return "", err
+				}
+				fmt.Fprintf(&text, "%0*d", digits, value)
+				count -= digits
+			case isALPHANUMERIC:
+				if count >= 2 {
+					value, err := read(11)
+					if err != nil {
+						return "", err
+					}
+					text.WriteByte(alphanumericCharset[value/45])
+					text.WriteByte(alphanumericCharset[value%45])
+					count -= 2
+				} else {
+					value, err := read(6)
+					if err != nil {
+						return "", err
+					}
+					text.WriteByte(alphanumericCharset[value])
+					count--
+				}
+			default:
+				value, err := read(8)
+				if err != nil {
+					return "", err
+				}
+				text.WriteByte(byte(value))
+				count--
+			}
+		}
+	}
+	return text.String(), nil
+}
+
+var decodeModules_TestData = []string{
+	"HELLO WORLD",
+	"0123456789012345",
+	"Nibh. Pulvinar enim porttitor tellus litora nec vestibulum sit montes class, euismod odio " +
+		"litora venenatis suscipit mi cras arcu a dictum risus vestibulum parturient pellentesque",
+}
+
+func Test_decodeModules(test *testing.T) {
+	for i, text := range decodeModules_TestData {
+		gen := Generator{}
+		gen = gen.EncodeText(text)
+		actual, err := decodeModules(gen.GetModules())
+		if err != nil || actual != text {
+			test.Errorf(
+				"decoder.Test_decodeModules[%d]:\n\tactual text -> %q (err: %v)\n is not equal to\n\texpected text -> %q",
+				i, actual, err, text,
+			)
+		}
+	}
+}
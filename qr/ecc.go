@@ -14,3 +14,22 @@ const (
 	eccQUARTILE
 	eccHIGH
 )
+
+// Error correction level of a QR Code symbol, exported for use with encoding methods.
+type EccLevel uint
+
+// Error correction levels which can be passed to encoding methods,
+// declared in the same order as eccType.
+const (
+	// Recovers about 7% of the symbol.
+	EccLow EccLevel = iota
+
+	// Recovers about 15% of the symbol.
+	EccMedium
+
+	// Recovers about 25% of the symbol.
+	EccQuartile
+
+	// Recovers about 30% of the symbol.
+	EccHigh
+)
@@ -0,0 +1,253 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+)
+
+// Default height of caption letters measured in modules.
+const defaultCaptionSize = 3.5
+
+// Minimum light area between a framed code and the frame line measured
+// in modules, the quiet zone required by ISO/IEC 18004.
+const minFramedQuietZone = 4
+
+// Describes a frame drawn around a code, optionally with a caption below it,
+// e.g. "Scan to pay". All distances are measured in modules. The frame is
+// drawn outside of the quiet zone, so the quiet zone is never covered by it.
+// The quiet zone together with Padding must be at least 4 modules wide.
+//
+// Inside the frame everything takes the background color of the style,
+// outside of its rounded corners raster images are transparent.
+type Frame struct {
+	// Text below the code, drawn with the embedded 5x7 bitmap font,
+	// so only printable ASCII characters are supported.
+	Caption string
+
+	// Height of caption letters, 3.5 modules if zero.
+	CaptionSize float64
+
+	// Width of the frame line, no line is drawn if zero.
+	Border uint
+
+	// Radius of the outer corners of the frame.
+	CornerRadius float64
+
+	// Space between the quiet zone and the frame line, as well as
+	// between the caption and the frame line.
+	Padding uint
+
+	// Color of the frame line and the caption, black if nil.
+	Color color.Color
+}
+
+// Geometry of a frame around a code, measured in modules.
+type frameLayout struct {
+	width, height float64
+
+	// Top left corner of the code including the quiet zone.
+	codeX, codeY float64
+
+	// Top left corner of the caption and the side of a font pixel.
+	captionX, captionY, pixel float64
+
+	border, radius float64
+}
+
+// Returns the color of the frame line and the caption.
+func (f Frame) color() color.RGBA {
+	if f.Color == nil {
+		return color.RGBA{A: 255}
+	}
+	return toRGBA(f.Color)
+}
+
+// Returns the geometry of the frame around a code which takes
+// codeSide modules including the quiet zone of quietZone modules.
+func (f Frame) layout(method string, codeSide float64, quietZone uint) (frameLayout, error) {
+	if quietZone+f.Padding < minFramedQuietZone {
+		return frameLayout{}, generatorErr(method, fmt.Sprintf("quiet zone and padding of a framed code must be at least %d modules", minFramedQuietZone))
+	}
+	if f.CaptionSize < 0 {
+		return frameLayout{}, generatorErr(method, "caption size must not be negative")
+	}
+	if f.CornerRadius < 0 {
+		return frameLayout{}, generatorErr(method, "corner radius must not be negative")
+	}
+	for _, c := range f.Caption {
+		if !hasGlyph(c) {
+			return frameLayout{}, generatorErr(method, fmt.Sprintf("caption character %q is not supported by the embedded font", c))
+		}
+	}
+	size := f.CaptionSize
+	if size == 0 {
+		size = defaultCaptionSize
+	}
+	l := frameLayout{pixel: size / glyphHeight, border: float64(f.Border)}
+	inset := float64(f.Border + f.Padding)
+	captionWidth := float64(textWidth(f.Caption)) * l.pixel
+	contentWidth := math.Max(codeSide, captionWidth)
+	l.width = contentWidth + inset*2
+	l.height = codeSide + inset*2
+	if f.Caption != "" {
+		l.height += size + float64(f.Padding)
+	}
+	l.codeX = inset + (contentWidth-codeSide)/2
+	l.codeY = inset
+	l.captionX = inset + (contentWidth-captionWidth)/2
+	l.captionY = inset + codeSide
+	l.radius = math.Min(f.CornerRadius, math.Min(l.width, l.height)/2)
+	return l, nil
+}
+
+// Returns generated QR Code drawn with the given style inside the given frame.
+// Every module is moduleSize x moduleSize pixels, margin is measured in modules.
+func (gen *Generator) FramedImage(frame Frame, style Style, margin, moduleSize uint) (image.Image, error) {
+	return gen.framedImage("FramedImage", frame, style, margin, moduleSize)
+}
+
+// Writes generated QR Code drawn with the given style inside
+// the given frame to w as an image of the given format.
+func (gen *Generator) EncodeFramedImage(w io.Writer, format ImageFormat, frame Frame, style Style, margin, moduleSize uint) error {
+	img, err := gen.framedImage("EncodeFramedImage", frame, style, margin, moduleSize)
+	if err != nil {
+		return err
+	}
+	return encodeImage(w, img, format)
+}
+
+// Returns svg string of generated QR Code drawn with the given style inside the given frame.
+func (gen *Generator) ToFramedSvg(frame Frame, style Style, border int) (string, error) {
+	if border < 0 {
+		return "", generatorErr("ToFramedSvg", "negative border was given")
+	}
+	if border > math.MaxInt64/2 || border*2 > math.MaxInt64-gen.size {
+		return "", generatorErr("ToFramedSvg", "border too large")
+	}
+	l, err := frame.layout("ToFramedSvg", float64(gen.size+border*2), uint(border))
+	if err != nil {
+		return "", err
+	}
+	body, err := gen.styledSvgBody("ToFramedSvg", style, border)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+		"<!DOCTYPE svg PUBLIC \"-//W3C//DTD SVG 1.1//EN\" \"http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd\">\n"+
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" xmlns:xlink=\"http://www.w3.org/1999/xlink\" version=\"1.1\" "+
+		"viewBox=\"0 0 %s %s\" stroke=\"none\">\n", fmtFloat(l.width), fmtFloat(l.height))
+	background := svgColor(style.background())
+	if l.border > 0 {
+		fmt.Fprintf(&buf, "\t<rect width=\"%s\" height=\"%s\" rx=\"%s\" fill=\"%s\"/>\n",
+			fmtFloat(l.width), fmtFloat(l.height), fmtFloat(l.radius), svgColor(frame.color()))
+	}
+	fmt.Fprintf(&buf, "\t<rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" rx=\"%s\" fill=\"%s\"/>\n",
+		fmtFloat(l.border), fmtFloat(l.border), fmtFloat(l.width-l.border*2), fmtFloat(l.height-l.border*2),
+		fmtFloat(math.Max(0, l.radius-l.border)), background)
+	if frame.Caption != "" {
+		fmt.Fprintf(&buf, "\t<path d=\"%s\" fill=\"%s\"/>\n", captionSvgPath(frame.Caption, l), svgColor(frame.color()))
+	}
+	fmt.Fprintf(&buf, "\t<g transform=\"translate(%s,%s)\">\n%s\t</g>\n</svg>", fmtFloat(l.codeX), fmtFloat(l.codeY), body)
+	return buf.String(), nil
+}
+
+// Helper method for framed raster renderers.
+func (gen *Generator) framedImage(method string, frame Frame, style Style, margin, moduleSize uint) (image.Image, error) {
+	l, err := frame.layout(method, float64(gen.getSize()+int(margin)*2), margin)
+	if err != nil {
+		return nil, err
+	}
+	code, err := gen.styledImage(method, style, margin, moduleSize)
+	if err != nil {
+		return nil, err
+	}
+	scale := float64(moduleSize)
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(l.width*scale)), int(math.Ceil(l.height*scale))))
+	background, foreground := style.background(), frame.color()
+	for py := 0; py < img.Bounds().Dy(); py++ {
+		for px := 0; px < img.Bounds().Dx(); px++ {
+			var r, g, b, a int
+			for sy := 0; sy < styleSamples; sy++ {
+				for sx := 0; sx < styleSamples; sx++ {
+					fx := (float64(px) + (float64(sx)+0.5)/styleSamples) / scale
+					fy := (float64(py) + (float64(sy)+0.5)/styleSamples) / scale
+					var c color.RGBA
+					switch {
+					case !insideRoundedBox(fx, fy, 0, 0, l.width, l.height, l.radius):
+					case !insideRoundedBox(fx, fy, l.border, l.border, l.width-l.border*2, l.height-l.border*2,
+						math.Max(0, l.radius-l.border)):
+						c = foreground
+					case captionPixel(frame.Caption, l, fx, fy):
+						c = foreground
+					default:
+						c = background
+					}
+					r, g, b, a = r+int(c.R), g+int(c.G), b+int(c.B), a+int(c.A)
+				}
+			}
+			n := styleSamples * styleSamples
+			img.SetRGBA(px, py, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: uint8(a / n)})
+		}
+	}
+	origin := image.Pt(int(math.Floor(l.codeX*scale+0.5)), int(math.Floor(l.codeY*scale+0.5)))
+	draw.Draw(img, code.Bounds().Add(origin), code, image.Point{}, draw.Src)
+	return img, nil
+}
+
+// Returns true if the point (x, y) lies inside the box with the given top left
+// corner and size whose corners are rounded with the given radius.
+func insideRoundedBox(x, y, left, top, width, height, radius float64) bool {
+	if x < left || y < top || x >= left+width || y >= top+height {
+		return false
+	}
+	cx := math.Max(left+radius, math.Min(x, left+width-radius))
+	cy := math.Max(top+radius, math.Min(y, top+height-radius))
+	return math.Hypot(x-cx, y-cy) <= radius
+}
+
+// Returns true if the point (x, y) lies inside a set pixel of the caption.
+func captionPixel(caption string, l frameLayout, x, y float64) bool {
+	gx := int(math.Floor((x - l.captionX) / l.pixel))
+	gy := int(math.Floor((y - l.captionY) / l.pixel))
+	if gx < 0 || gy < 0 || gy >= glyphHeight {
+		return false
+	}
+	runes := []rune(caption)
+	if i := gx / glyphAdvance; i < len(runes) {
+		return glyphPixel(runes[i], gx%glyphAdvance, gy)
+	}
+	return false
+}
+
+// Returns SVG path data of the caption, every horizontal run
+// of set glyph pixels is drawn as a single rectangle.
+func captionSvgPath(caption string, l frameLayout) string {
+	var buf bytes.Buffer
+	for i, c := range []rune(caption) {
+		for y := 0; y < glyphHeight; y++ {
+			for x := 0; x < glyphWidth; x++ {
+				if !glyphPixel(c, x, y) || glyphPixel(c, x-1, y) {
+					continue
+				}
+				run := 1
+				for glyphPixel(c, x+run, y) {
+					run++
+				}
+				fmt.Fprintf(&buf, "M%s,%sh%sv%sh-%sz",
+					fmtFloat(l.captionX+float64(i*glyphAdvance+x)*l.pixel), fmtFloat(l.captionY+float64(y)*l.pixel),
+					fmtFloat(float64(run)*l.pixel), fmtFloat(l.pixel), fmtFloat(float64(run)*l.pixel))
+			}
+		}
+	}
+	return buf.String()
+}
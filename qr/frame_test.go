@@ -0,0 +1,180 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+var testFrame = Frame{Caption: "Scan to pay", Border: 1, Padding: 2, CornerRadius: 3, Color: testDarkRed}
+
+var frameLayout_TestData = []struct {
+	frame     Frame
+	quietZone uint
+	expected  frameLayout
+}{
+	{
+		frame:     Frame{},
+		quietZone: 4,
+		expected: frameLayout{
+			width: 29, height: 29, captionX: 14.5, captionY: 29, pixel: 0.5,
+		},
+	},
+	{
+		frame:     testFrame,
+		quietZone: 2,
+		expected: frameLayout{
+			width: 38.5, height: 40.5, codeX: 4.75, codeY: 3,
+			captionX: 3, captionY: 32, pixel: 0.5, border: 1, radius: 3,
+		},
+	},
+	{
+		frame:     Frame{Caption: "Hi", CaptionSize: 7, Padding: 1, CornerRadius: 100},
+		quietZone: 3,
+		expected: frameLayout{
+			width: 31, height: 39, codeX: 1, codeY: 1,
+			captionX: 10, captionY: 30, pixel: 1, radius: 15.5,
+		},
+	},
+}
+
+func Test_frameLayout(test *testing.T) {
+	for i, data := range frameLayout_TestData {
+		actual, err := data.frame.layout("layout", 29, data.quietZone)
+		if err != nil || actual != data.expected {
+			test.Errorf(
+				"frame.Test_frameLayout[%d]:\n\tactual -> %+v (err: %v)\n is not equal to\n\texpected -> %+v",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+}
+
+var frameLayoutErr_TestData = []struct {
+	frame    Frame
+	expected error
+}{
+	{
+		frame:    Frame{CaptionSize: -1},
+		expected: generatorErr("layout", "caption size must not be negative"),
+	},
+	{
+		frame:    Frame{CornerRadius: -1},
+		expected: generatorErr("layout", "corner radius must not be negative"),
+	},
+	{
+		frame:    Frame{Caption: "Scan → pay"},
+		expected: generatorErr("layout", "caption character '→' is not supported by the embedded font"),
+	},
+}
+
+func Test_frameLayoutQuietZone(test *testing.T) {
+	expected := generatorErr("layout", "quiet zone and padding of a framed code must be at least 4 modules")
+	for i, data := range []struct {
+		frame     Frame
+		quietZone uint
+	}{
+		{frame: Frame{}, quietZone: 0},
+		{frame: Frame{Border: 1}, quietZone: 3},
+		{frame: Frame{Caption: "Hi", Padding: 1}, quietZone: 2},
+	} {
+		if _, actual := data.frame.layout("layout", 29, data.quietZone); actual == nil || actual.Error() != expected.Error() {
+			test.Errorf(
+				"frame.Test_frameLayoutQuietZone[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, expected,
+			)
+		}
+	}
+}
+
+func Test_frameLayoutErr(test *testing.T) {
+	for i, data := range frameLayoutErr_TestData {
+		_, actual := data.frame.layout("layout", 29, 4)
+		if actual == nil || actual.Error() != data.expected.Error() {
+			test.Errorf(
+				"frame.Test_frameLayoutErr[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+func Test_FramedImage(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	var buf bytes.Buffer
+	if err := gen.EncodeFramedImage(&buf, FormatPNG, testFrame, Style{}, 4, 2); err != nil {
+		test.Fatalf("frame.Test_FramedImage:\n\tunexpected error -> %s", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		test.Fatalf("frame.Test_FramedImage:\n\tdecoding failed -> %s", err)
+	}
+	if actual := img.Bounds(); actual != image.Rect(0, 0, 77, 81) {
+		test.Errorf("frame.Test_FramedImage:\n\tactual bounds -> %v\n is not equal to\n\texpected -> %v", actual, image.Rect(0, 0, 77, 81))
+	}
+	pixels := []struct {
+		name     string
+		x, y     int
+		expected color.RGBA
+	}{
+		{name: "rounded corner", x: 0, y: 0, expected: color.RGBA{}},
+		{name: "frame line", x: 38, y: 0, expected: testDarkRed},
+		{name: "padding", x: 38, y: 4, expected: color.RGBA{R: 255, G: 255, B: 255, A: 255}},
+		{name: "quiet zone", x: 12, y: 8, expected: color.RGBA{R: 255, G: 255, B: 255, A: 255}},
+		{name: "caption", x: 6, y: 65, expected: testDarkRed},
+	}
+	for _, pixel := range pixels {
+		if actual := toRGBA(img.At(pixel.x, pixel.y)); actual != pixel.expected {
+			test.Errorf("frame.Test_FramedImage:\n\tactual %s color -> %v\n is not equal to\n\texpected -> %v",
+				pixel.name, actual, pixel.expected)
+		}
+	}
+	code := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}).SubImage(image.Rect(10, 6, 68, 64))
+	if actual, err := decodeModules(sampleModules(code, gen.size, 4, 2)); err != nil || actual != "HELLO WORLD" {
+		test.Errorf("frame.Test_FramedImage:\n\tactual text -> %q (err: %v)", actual, err)
+	}
+	if _, err := gen.FramedImage(Frame{Caption: "\t"}, Style{}, 4, 2); err == nil {
+		test.Errorf("frame.Test_FramedImage:\n\tunsupported caption is accepted")
+	}
+	if _, err := gen.FramedImage(Frame{Caption: "Hi", Border: 1}, Style{}, 0, 2); err == nil {
+		test.Errorf("frame.Test_FramedImage:\n\tmissing quiet zone is accepted")
+	}
+}
+
+func Test_ToFramedSvg(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	svg, err := gen.ToFramedSvg(testFrame, Style{}, 4)
+	if err != nil {
+		test.Fatalf("frame.Test_ToFramedSvg:\n\tunexpected error -> %s", err)
+	}
+	styled, _ := gen.ToStyledSvg(Style{}, 4)
+	body := styled[strings.Index(styled, "\t<path"):strings.Index(styled, "</svg>")]
+	for _, expected := range []string{
+		"viewBox=\"0 0 38.5 40.5\"",
+		"\t<rect width=\"38.5\" height=\"40.5\" rx=\"3\" fill=\"#800000\"/>\n",
+		"\t<rect x=\"1\" y=\"1\" width=\"36.5\" height=\"38.5\" rx=\"2\" fill=\"#FFFFFF\"/>\n",
+		"\t<path d=\"M3.5,32h2v0.5h-2zM3,32.5h0.5v0.5h-0.5z",
+		"\t<g transform=\"translate(4.75,3)\">\n" + body + "\t</g>\n</svg>",
+	} {
+		if !strings.Contains(svg, expected) {
+			test.Errorf("frame.Test_ToFramedSvg:\n\tsvg -> %s\n does not contain\n\texpected -> %s", svg, expected)
+		}
+	}
+	if _, err := gen.ToFramedSvg(testFrame, Style{}, -1); err == nil {
+		test.Errorf("frame.Test_ToFramedSvg:\n\tnegative border is accepted")
+	}
+	if _, err := gen.ToFramedSvg(Frame{Caption: "Hi", Border: 1}, Style{}, 0); err == nil {
+		test.Errorf("frame.Test_ToFramedSvg:\n\tmissing quiet zone is accepted")
+	}
+}
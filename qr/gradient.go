@@ -0,0 +1,183 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// Minimum contrast ratio (as defined by WCAG) between every gradient stop
+// and the background for the code to stay readable.
+const minGradientContrast = 4.5
+
+// Represents the geometry of a gradient.
+type GradientKind int
+
+const (
+	// Colors change along a line crossing the symbol at the given angle.
+	LinearGradient GradientKind = iota
+
+	// Colors change with the distance from the center of the symbol.
+	RadialGradient
+)
+
+// A color of a gradient at the given offset in the range [0, 1].
+type GradientStop struct {
+	Offset float64
+	Color  color.Color
+}
+
+// Describes a gradient fill of black modules. The gradient spans the symbol
+// without the quiet zone: a linear gradient runs from one edge to the opposite
+// one, a radial gradient runs from the center to the corners.
+type Gradient struct {
+	Kind GradientKind
+
+	// Direction of a linear gradient in degrees, clockwise:
+	// 0 is left to right, 90 is top to bottom.
+	Angle float64
+
+	// At least one color stop, every stop must be opaque and contrast with the background.
+	Stops []GradientStop
+}
+
+// Returns an error if the gradient has no stops or any of its stops does
+// not contrast enough with the given background.
+func (g Gradient) validate(method string, background color.Color) error {
+	if len(g.Stops) == 0 {
+		return generatorErr(method, "gradient has no stops")
+	}
+	if g.Kind != LinearGradient && g.Kind != RadialGradient {
+		return generatorErr(method, fmt.Sprintf("unknown gradient kind %d", g.Kind))
+	}
+	bgLuminance := relativeLuminance(background)
+	for i, stop := range g.Stops {
+		if stop.Color == nil {
+			return generatorErr(method, fmt.Sprintf("gradient stop %d has no color", i))
+		}
+		// Luminance ignores alpha, and a translucent stop shows the background through.
+		if _, _, _, a := stop.Color.RGBA(); a != 0xffff {
+			return generatorErr(method, fmt.Sprintf("gradient stop %d is not opaque", i))
+		}
+		luminance := relativeLuminance(stop.Color)
+		if luminance >= bgLuminance {
+			return generatorErr(method, fmt.Sprintf("gradient stop %d is not darker than the background", i))
+		}
+		if ratio := (bgLuminance + 0.05) / (luminance + 0.05); ratio < minGradientContrast {
+			return generatorErr(method, fmt.Sprintf(
+				"gradient stop %d has contrast %.2f:1 against the background, at least %.1f:1 is required",
+				i, ratio, minGradientContrast,
+			))
+		}
+	}
+	return nil
+}
+
+// Returns a copy of the gradient with stops ordered by offset and offsets
+// clamped to [0, 1]. Renderers sort the stops once, before calling colorAt.
+func (g Gradient) sorted() *Gradient {
+	stops := make([]GradientStop, len(g.Stops))
+	for i, stop := range g.Stops {
+		stop.Offset = math.Max(0, math.Min(1, stop.Offset))
+		// Insertion sort keeps stops with equal offsets in the given order.
+		j := i
+		for ; j > 0 && stops[j-1].Offset > stop.Offset; j-- {
+			stops[j] = stops[j-1]
+		}
+		stops[j] = stop
+	}
+	g.Stops = stops
+	return &g
+}
+
+// Returns the gradient color at the point (x, y) measured in modules
+// from the top left corner of a symbol with the given size.
+// The stops must be sorted (see sorted).
+func (g Gradient) colorAt(x, y float64, size int) color.RGBA {
+	stops := g.Stops
+	t := g.offsetAt(x, y, float64(size))
+	if t <= stops[0].Offset {
+		return toRGBA(stops[0].Color)
+	}
+	for i := 1; i < len(stops); i++ {
+		if t <= stops[i].Offset {
+			span := stops[i].Offset - stops[i-1].Offset
+			if span == 0 {
+				return toRGBA(stops[i].Color)
+			}
+			return blendColors(toRGBA(stops[i].Color), toRGBA(stops[i-1].Color), (t-stops[i-1].Offset)/span)
+		}
+	}
+	return toRGBA(stops[len(stops)-1].Color)
+}
+
+// Returns the gradient offset at the point (x, y) of a symbol with the given size.
+func (g Gradient) offsetAt(x, y, size float64) float64 {
+	c := size / 2
+	if g.Kind == RadialGradient {
+		return math.Hypot(x-c, y-c) / (c * math.Sqrt2)
+	}
+	dx, dy, half := g.direction(size)
+	return ((x-c)*dx + (y-c)*dy + half) / (2 * half)
+}
+
+// Returns the unit direction of a linear gradient and the half of its length,
+// which is the projection of the symbol onto that direction.
+func (g Gradient) direction(size float64) (dx, dy, half float64) {
+	rad := g.Angle * math.Pi / 180
+	dx, dy = math.Cos(rad), math.Sin(rad)
+	return dx, dy, (math.Abs(dx) + math.Abs(dy)) * size / 2
+}
+
+// Returns SVG definition of the gradient with the given id for a symbol
+// of the given size drawn with the given border.
+func (g Gradient) svgDef(id string, size, border int) string {
+	var buf bytes.Buffer
+	c := float64(size)/2 + float64(border)
+	if g.Kind == RadialGradient {
+		fmt.Fprintf(&buf, "\t\t<radialGradient id=\"%s\" gradientUnits=\"userSpaceOnUse\" cx=\"%s\" cy=\"%s\" r=\"%s\">\n",
+			id, fmtFloat(c), fmtFloat(c), fmtFloat(float64(size)/2*math.Sqrt2))
+	} else {
+		dx, dy, half := g.direction(float64(size))
+		fmt.Fprintf(&buf, "\t\t<linearGradient id=\"%s\" gradientUnits=\"userSpaceOnUse\" x1=\"%s\" y1=\"%s\" x2=\"%s\" y2=\"%s\">\n",
+			id, fmtFloat(c-dx*half), fmtFloat(c-dy*half), fmtFloat(c+dx*half), fmtFloat(c+dy*half))
+	}
+	for _, stop := range g.sorted().Stops {
+		fmt.Fprintf(&buf, "\t\t\t<stop offset=\"%s\" stop-color=\"%s\"/>\n", fmtFloat(stop.Offset), svgColor(stop.Color))
+	}
+	if g.Kind == RadialGradient {
+		buf.WriteString("\t\t</radialGradient>\n")
+	} else {
+		buf.WriteString("\t\t</linearGradient>\n")
+	}
+	return buf.String()
+}
+
+// Returns the relative luminance of a color as defined by WCAG.
+func relativeLuminance(c color.Color) float64 {
+	rgba := toRGBA(c)
+	channel := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(rgba.R) + 0.7152*channel(rgba.G) + 0.0722*channel(rgba.B)
+}
+
+// Converts any color to 8-bit RGBA.
+func toRGBA(c color.Color) color.RGBA {
+	return color.RGBAModel.Convert(c).(color.RGBA)
+}
+
+// Returns a color in the SVG #RRGGBB notation.
+func svgColor(c color.Color) string {
+	rgba := toRGBA(c)
+	return fmt.Sprintf("#%02X%02X%02X", rgba.R, rgba.G, rgba.B)
+}
@@ -0,0 +1,237 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+var (
+	testDarkBlue = color.RGBA{R: 0, G: 0, B: 128, A: 255}
+	testDarkRed  = color.RGBA{R: 128, G: 0, B: 0, A: 255}
+)
+
+var gradientValidate_TestData = []struct {
+	gradient   Gradient
+	background color.Color
+	expected   error
+}{
+	{
+		gradient:   Gradient{Stops: []GradientStop{{0, testDarkBlue}, {1, testDarkRed}}},
+		background: color.White,
+		expected:   nil,
+	},
+	{
+		gradient:   Gradient{},
+		background: color.White,
+		expected:   generatorErr("validate", "gradient has no stops"),
+	},
+	{
+		gradient:   Gradient{Kind: GradientKind(5), Stops: []GradientStop{{0, testDarkBlue}}},
+		background: color.White,
+		expected:   generatorErr("validate", "unknown gradient kind 5"),
+	},
+	{
+		gradient:   Gradient{Stops: []GradientStop{{0, testDarkBlue}, {1, color.RGBA{R: 200, G: 200, B: 200, A: 255}}}},
+		background: color.White,
+		expected:   generatorErr("validate", "gradient stop 1 has contrast 1.67:1 against the background, at least 4.5:1 is required"),
+	},
+	{
+		gradient:   Gradient{Stops: []GradientStop{{0, color.White}}},
+		background: color.Black,
+		expected:   generatorErr("validate", "gradient stop 0 is not darker than the background"),
+	},
+	{
+		gradient:   Gradient{Stops: []GradientStop{{0, testDarkBlue}, {1, color.Transparent}}},
+		background: color.White,
+		expected:   generatorErr("validate", "gradient stop 1 is not opaque"),
+	},
+	{
+		gradient:   Gradient{Stops: []GradientStop{{0, color.NRGBA{A: 128}}}},
+		background: color.White,
+		expected:   generatorErr("validate", "gradient stop 0 is not opaque"),
+	},
+	{
+		gradient:   Gradient{Stops: []GradientStop{{0, nil}}},
+		background: color.White,
+		expected:   generatorErr("validate", "gradient stop 0 has no color"),
+	},
+}
+
+func Test_gradientValidate(test *testing.T) {
+	for i, data := range gradientValidate_TestData {
+		actual := data.gradient.validate("validate", data.background)
+		if (actual == nil) != (data.expected == nil) || actual != nil && actual.Error() != data.expected.Error() {
+			test.Errorf(
+				"gradient.Test_gradientValidate[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+var gradientColorAt_TestData = []struct {
+	gradient Gradient
+	x, y     float64
+	expected color.RGBA
+}{
+	{
+		gradient: Gradient{Stops: []GradientStop{{1, testDarkRed}, {0, testDarkBlue}}},
+		x:        0, y: 5,
+		expected: testDarkBlue,
+	},
+	{
+		gradient: Gradient{Stops: []GradientStop{{0, testDarkBlue}, {1, testDarkRed}}},
+		x:        10, y: 0,
+		expected: testDarkRed,
+	},
+	{
+		gradient: Gradient{Stops: []GradientStop{{0, color.Black}, {1, testDarkRed}}},
+		x:        5, y: 5,
+		expected: color.RGBA{R: 64, A: 255},
+	},
+	{
+		gradient: Gradient{Angle: 90, Stops: []GradientStop{{0, testDarkBlue}, {1, testDarkRed}}},
+		x:        0, y: 10,
+		expected: testDarkRed,
+	},
+	{
+		gradient: Gradient{Kind: RadialGradient, Stops: []GradientStop{{0, testDarkBlue}, {1, testDarkRed}}},
+		x:        5, y: 5,
+		expected: testDarkBlue,
+	},
+	{
+		gradient: Gradient{Kind: RadialGradient, Stops: []GradientStop{{0, testDarkBlue}, {1, testDarkRed}}},
+		x:        10, y: 10,
+		expected: testDarkRed,
+	},
+}
+
+func Test_gradientColorAt(test *testing.T) {
+	for i, data := range gradientColorAt_TestData {
+		actual := data.gradient.sorted().colorAt(data.x, data.y, 10)
+		if actual != data.expected {
+			test.Errorf(
+				"gradient.Test_gradientColorAt[%d]:\n\tactual color -> %v\n is not equal to\n\texpected color -> %v",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+var gradientSvgDef_TestData = []struct {
+	gradient Gradient
+	expected string
+}{
+	{
+		gradient: Gradient{Stops: []GradientStop{{0, testDarkBlue}, {1, testDarkRed}}},
+		expected: "\t\t<linearGradient id=\"fill\" gradientUnits=\"userSpaceOnUse\" x1=\"2\" y1=\"7\" x2=\"12\" y2=\"7\">\n" +
+			"\t\t\t<stop offset=\"0\" stop-color=\"#000080\"/>\n" +
+			"\t\t\t<stop offset=\"1\" stop-color=\"#800000\"/>\n" +
+			"\t\t</linearGradient>\n",
+	},
+	{
+		gradient: Gradient{Kind: RadialGradient, Stops: []GradientStop{{0.5, color.Black}}},
+		expected: "\t\t<radialGradient id=\"fill\" gradientUnits=\"userSpaceOnUse\" cx=\"7\" cy=\"7\" r=\"7.0711\">\n" +
+			"\t\t\t<stop offset=\"0.5\" stop-color=\"#000000\"/>\n" +
+			"\t\t</radialGradient>\n",
+	},
+}
+
+func Test_gradientSvgDef(test *testing.T) {
+	for i, data := range gradientSvgDef_TestData {
+		actual := data.gradient.svgDef("fill", 10, 2)
+		if actual != data.expected {
+			test.Errorf(
+				"gradient.Test_gradientSvgDef[%d]:\n\tactual -> %s\n is not equal to\n\texpected -> %s",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+func Test_StyledGradient(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	fill := &Gradient{Stops: []GradientStop{{0, testDarkBlue}, {1, testDarkRed}}}
+	img, err := gen.StyledImage(Style{Fill: fill}, 0, 2)
+	if err != nil {
+		test.Fatalf("gradient.Test_StyledGradient:\n\tunexpected error -> %s", err)
+	}
+	// Finder corners take the colors of the gradient at their pixel centers.
+	if left, expected := toRGBA(img.At(0, 0)), fill.colorAt(0.25, 0.25, 21); left != expected {
+		test.Errorf("gradient.Test_StyledGradient:\n\tactual left color -> %v\n is not equal to\n\texpected -> %v", left, expected)
+	}
+	if right, expected := toRGBA(img.At(41, 0)), fill.colorAt(20.75, 0.25, 21); right != expected {
+		test.Errorf("gradient.Test_StyledGradient:\n\tactual right color -> %v\n is not equal to\n\texpected -> %v", right, expected)
+	}
+	svg, err := gen.ToStyledSvg(Style{Fill: fill, Background: color.RGBA{R: 255, G: 255, B: 224, A: 255}}, 4)
+	if err != nil {
+		test.Fatalf("gradient.Test_StyledGradient:\n\tunexpected error -> %s", err)
+	}
+	for _, expected := range []string{"<linearGradient id=\"fill\"", "fill=\"url(#fill)\"", "fill=\"#FFFFE0\""} {
+		if !strings.Contains(svg, expected) {
+			test.Errorf("gradient.Test_StyledGradient:\n\tsvg -> %s\n does not contain\n\texpected -> %s", svg, expected)
+		}
+	}
+	fill.Stops = append(fill.Stops, GradientStop{0.5, color.RGBA{R: 255, G: 200, A: 255}})
+	if _, err = gen.StyledImage(Style{Fill: fill}, 0, 2); err == nil {
+		test.Errorf("gradient.Test_StyledGradient:\n\tlow contrast gradient is accepted")
+	}
+	if _, err = gen.ToStyledSvg(Style{Fill: fill}, 0); err == nil {
+		test.Errorf("gradient.Test_StyledGradient:\n\tlow contrast gradient is accepted")
+	}
+}
+
+func Test_WithGradient(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	fill := Gradient{Kind: RadialGradient, Stops: []GradientStop{{1, testDarkRed}, {0, testDarkBlue}}}
+	filled, err := gen.WithGradient(fill)
+	if err != nil {
+		test.Fatalf("gradient.Test_WithGradient:\n\tunexpected error -> %s", err)
+	}
+	var buf bytes.Buffer
+	if err = filled.EncodeImage(&buf, FormatPNG, 4, 58); err != nil {
+		test.Fatalf("gradient.Test_WithGradient:\n\tunexpected error -> %s", err)
+	}
+	img, _ := png.Decode(&buf)
+	// 29 modules of 2 pixels, black ones take the colors at their top left pixel centers.
+	sorted := fill.sorted()
+	for y := 0; y < 21; y++ {
+		for x := 0; x < 21; x++ {
+			expected := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+			if gen.getModule(x, y) {
+				expected = sorted.colorAt(float64(x)+0.25, float64(y)+0.25, 21)
+			}
+			if actual := toRGBA(img.At(x*2+8, y*2+8)); actual != expected {
+				test.Errorf(
+					"gradient.Test_WithGradient:\n\tactual color of module (%d, %d) -> %v\n is not equal to\n\texpected -> %v",
+					x, y, actual, expected,
+				)
+			}
+		}
+	}
+	svg, err := filled.ToSvg(4)
+	if err != nil {
+		test.Fatalf("gradient.Test_WithGradient:\n\tunexpected error -> %s", err)
+	}
+	for _, expected := range []string{"<radialGradient id=\"fill\"", "<stop offset=\"0\" stop-color=\"#000080\"/>", "fill=\"url(#fill)\""} {
+		if !strings.Contains(svg, expected) {
+			test.Errorf("gradient.Test_WithGradient:\n\tsvg -> %s\n does not contain\n\texpected -> %s", svg, expected)
+		}
+	}
+	if svg, _ = gen.ToSvg(4); strings.Contains(svg, "url(#fill)") {
+		test.Errorf("gradient.Test_WithGradient:\n\tgradient is applied to the original code")
+	}
+	fill.Stops = append(fill.Stops, GradientStop{0.5, color.RGBA{R: 255, G: 200, A: 255}})
+	if _, err = gen.WithGradient(fill); err == nil {
+		test.Errorf("gradient.Test_WithGradient:\n\tlow contrast gradient is accepted")
+	}
+}
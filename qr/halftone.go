@@ -0,0 +1,147 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/nfnt/resize"
+)
+
+const (
+	// Smallest module size in pixels which leaves room for the picture around the dot.
+	minHalftoneModuleSize = 3
+
+	// Smallest and default sides of the center dot as a fraction of the module side.
+	// Scanners sample about the central third of a module, so the dot has to cover
+	// more than a half of that area whatever the picture around it is, which takes
+	// a side of at least sqrt(1/2)/3 (about 0.236) of the module.
+	minHalftoneDotRatio     = 0.25
+	defaultHalftoneDotRatio = 1.0 / 3
+)
+
+// Describes a halftone QR Code which blends a picture into the symbol.
+// Every data module is drawn as a small center dot of its true color inside
+// a cell that otherwise takes the color of the dithered picture. Function
+// modules (as marked in isFunction) are kept fully solid.
+type Halftone struct {
+	// Picture shown through the code, scaled to the symbol without the quiet zone.
+	Picture image.Image
+
+	// Side of the center dot as a fraction of the module side, in the range
+	// [0.25, 1], rounded up to whole pixels. Zero means the default of 1/3.
+	// Larger dots decode more reliably.
+	DotRatio float64
+}
+
+// Returns generated QR Code drawn as a halftone image. Every module
+// is moduleSize x moduleSize pixels, margin is measured in modules.
+func (gen *Generator) HalftoneImage(halftone Halftone, margin, moduleSize uint) (image.Image, error) {
+	return gen.halftoneImage("HalftoneImage", halftone, margin, moduleSize)
+}
+
+// Writes generated QR Code drawn as a halftone image to w in the given format.
+func (gen *Generator) EncodeHalftoneImage(w io.Writer, format ImageFormat, halftone Halftone, margin, moduleSize uint) error {
+	img, err := gen.halftoneImage("EncodeHalftoneImage", halftone, margin, moduleSize)
+	if err != nil {
+		return err
+	}
+	return encodeImage(w, img, format)
+}
+
+// Helper method for halftone renderers.
+func (gen *Generator) halftoneImage(method string, halftone Halftone, margin, moduleSize uint) (image.Image, error) {
+	if halftone.Picture == nil {
+		return nil, generatorErr(method, "halftone has no picture")
+	}
+	if moduleSize < minHalftoneModuleSize {
+		return nil, generatorErr(method, fmt.Sprintf("module size must be at least %d pixels", minHalftoneModuleSize))
+	}
+	ratio := halftone.DotRatio
+	if ratio == 0 {
+		ratio = defaultHalftoneDotRatio
+	}
+	if ratio < minHalftoneDotRatio || ratio > 1 {
+		return nil, generatorErr(method, fmt.Sprintf("dot ratio must be in range [%g, 1]", minHalftoneDotRatio))
+	}
+	return gen.drawHalftone(halftone.Picture, ratio, int(margin), int(moduleSize)), nil
+}
+
+// Draws generated QR Code as a halftone image of the given picture with
+// the given dot ratio, margin in modules and module size in pixels.
+func (gen *Generator) drawHalftone(picture image.Image, ratio float64, margin, cell int) *image.Gray {
+	// Rounding up keeps the dot over a half of the central third of the module
+	// at every module size (see minHalftoneDotRatio). The tolerance keeps exact
+	// products, such as 0.3 * 10, from being rounded up past a whole pixel.
+	dot := int(math.Ceil(ratio*float64(cell) - 1e-9))
+	dotStart := (cell - dot) / 2
+	symbolSide := gen.getSize() * cell
+	dithered := ditherImage(resize.Resize(uint(symbolSide), uint(symbolSide), picture, resize.Bilinear))
+
+	offset := margin * cell
+	side := symbolSide + offset*2
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	for y := 0; y < gen.getSize(); y++ {
+		for x := 0; x < gen.getSize(); x++ {
+			var black color.Gray
+			if !gen.module(x, y) {
+				black.Y = 255
+			}
+			for py := 0; py < cell; py++ {
+				for px := 0; px < cell; px++ {
+					c := black
+					inDot := dotStart <= px && px < dotStart+dot && dotStart <= py && py < dotStart+dot
+					if !gen.isFunction[y][x] && !inDot {
+						c = color.Gray{Y: dithered.Pix[dithered.PixOffset(x*cell+px, y*cell+py)]}
+					}
+					img.SetGray(offset+x*cell+px, offset+y*cell+py, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// Returns a black and white version of img produced by
+// Floyd-Steinberg error diffusion dithering.
+func ditherImage(img image.Image) *image.Gray {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	levels := make([]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			levels[y*width+x] = float64(color.GrayModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray).Y)
+		}
+	}
+	result := image.NewGray(image.Rect(0, 0, width, height))
+	spread := func(x, y int, e float64) {
+		if 0 <= x && x < width && y < height {
+			levels[y*width+x] += e
+		}
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			old := levels[y*width+x]
+			value := 0.0
+			if old >= 128 {
+				value = 255
+			}
+			result.Pix[y*result.Stride+x] = uint8(value)
+			e := old - value
+			spread(x+1, y, e*7/16)
+			spread(x-1, y+1, e*3/16)
+			spread(x, y+1, e*5/16)
+			spread(x+1, y+1, e*1/16)
+		}
+	}
+	return result
+}
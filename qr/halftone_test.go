@@ -0,0 +1,195 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// Returns a picture with a diagonal gray gradient.
+func testHalftonePicture(side int) image.Image {
+	img := image.NewGray(image.Rect(0, 0, side, side))
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8((x + y) * 255 / (2 * side))})
+		}
+	}
+	return img
+}
+
+var HalftoneImage_TestData = []struct {
+	text       string
+	dotRatio   float64
+	margin     uint
+	moduleSize uint
+}{
+	{text: "HELLO WORLD", dotRatio: 0, margin: 4, moduleSize: 6},
+	{text: "https://github.com/YuriyLisovskiy/qrcode", dotRatio: 0.25, margin: 2, moduleSize: 9},
+	{text: "Halftone codes blend a picture into the symbol", dotRatio: 0.5, margin: 0, moduleSize: 4},
+}
+
+func Test_HalftoneImage(test *testing.T) {
+	for i, data := range HalftoneImage_TestData {
+		gen := Generator{}
+		gen = gen.EncodeText(data.text)
+		halftone := Halftone{Picture: testHalftonePicture(64), DotRatio: data.dotRatio}
+		img, err := gen.HalftoneImage(halftone, data.margin, data.moduleSize)
+		if err != nil {
+			test.Fatalf("halftone.Test_HalftoneImage[%d]:\n\tunexpected error -> %s", i, err)
+		}
+		actual, err := decodeModules(sampleModules(img, gen.size, int(data.margin), int(data.moduleSize)))
+		if err != nil || actual != data.text {
+			test.Errorf(
+				"halftone.Test_HalftoneImage[%d]:\n\tactual text -> %q (err: %v)\n is not equal to\n\texpected text -> %q",
+				i, actual, err, data.text,
+			)
+		}
+		cell := int(data.moduleSize)
+		offset := int(data.margin) * cell
+		blended := 0
+		for y := 0; y < gen.size; y++ {
+			for x := 0; x < gen.size; x++ {
+				for py := 0; py < cell; py++ {
+					for px := 0; px < cell; px++ {
+						dark := isDarkColor(img.At(offset+x*cell+px, offset+y*cell+py))
+						if gen.isFunction[y][x] && dark != gen.module(x, y) {
+							test.Fatalf("halftone.Test_HalftoneImage[%d]:\n\tfunction module (%d, %d) is not solid", i, x, y)
+						}
+						if !gen.isFunction[y][x] && dark != gen.module(x, y) {
+							blended++
+						}
+					}
+				}
+			}
+		}
+		if blended == 0 {
+			test.Errorf("halftone.Test_HalftoneImage[%d]:\n\tpicture is not blended into data modules", i)
+		}
+	}
+}
+
+// Returns a picture of a single color.
+func testUniformPicture(c color.Color) image.Image {
+	img := image.NewGray(image.Rect(0, 0, 16, 16))
+	for i := range img.Pix {
+		img.Pix[i] = color.GrayModel.Convert(c).(color.Gray).Y
+	}
+	return img
+}
+
+var halftoneDotRatio_TestData = []struct {
+	picture    image.Image
+	ratio      float64
+	moduleSize int
+	decodes    bool
+}{
+	// A uniform picture of the opposite color is the worst case for dots.
+	{picture: testUniformPicture(color.White), ratio: minHalftoneDotRatio, moduleSize: 3, decodes: true},
+	{picture: testUniformPicture(color.White), ratio: minHalftoneDotRatio, moduleSize: 9, decodes: true},
+	{picture: testUniformPicture(color.Black), ratio: minHalftoneDotRatio, moduleSize: 10, decodes: true},
+	{picture: testUniformPicture(color.White), ratio: minHalftoneDotRatio, moduleSize: 13, decodes: true},
+	{picture: testUniformPicture(color.Black), ratio: 0.3, moduleSize: 10, decodes: true},
+	{picture: testUniformPicture(color.White), ratio: 0.2, moduleSize: 10, decodes: false},
+	{picture: testUniformPicture(color.Black), ratio: 0.2, moduleSize: 10, decodes: false},
+	{picture: testUniformPicture(color.White), ratio: 0.15, moduleSize: 12, decodes: false},
+}
+
+func Test_halftoneDotRatio(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("https://github.com/YuriyLisovskiy/qrcode")
+	for i, data := range halftoneDotRatio_TestData {
+		img := gen.drawHalftone(data.picture, data.ratio, 1, data.moduleSize)
+		actual, err := decodeModules(sampleModules(img, gen.size, 1, data.moduleSize))
+		if decodes := err == nil && actual == "https://github.com/YuriyLisovskiy/qrcode"; decodes != data.decodes {
+			test.Errorf(
+				"halftone.Test_halftoneDotRatio[%d]:\n\tactual decodes -> %v (text: %q, err: %v)\n is not equal to\n\texpected -> %v",
+				i, decodes, actual, err, data.decodes,
+			)
+		}
+	}
+}
+
+func Test_EncodeHalftoneImage(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	var buf bytes.Buffer
+	err := gen.EncodeHalftoneImage(&buf, FormatPNG, Halftone{Picture: testHalftonePicture(16)}, 1, 3)
+	if err != nil {
+		test.Fatalf("halftone.Test_EncodeHalftoneImage:\n\tunexpected error -> %s", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		test.Fatalf("halftone.Test_EncodeHalftoneImage:\n\tdecoding failed -> %s", err)
+	}
+	if actual, err := decodeModules(sampleModules(img, gen.size, 1, 3)); err != nil || actual != "HELLO WORLD" {
+		test.Errorf("halftone.Test_EncodeHalftoneImage:\n\tactual text -> %q (err: %v)", actual, err)
+	}
+}
+
+var HalftoneImageErr_TestData = []struct {
+	halftone   Halftone
+	moduleSize uint
+	expected   error
+}{
+	{
+		halftone:   Halftone{},
+		moduleSize: 6,
+		expected:   generatorErr("HalftoneImage", "halftone has no picture"),
+	},
+	{
+		halftone:   Halftone{Picture: testHalftonePicture(4)},
+		moduleSize: 2,
+		expected:   generatorErr("HalftoneImage", "module size must be at least 3 pixels"),
+	},
+	{
+		halftone:   Halftone{Picture: testHalftonePicture(4), DotRatio: 0.1},
+		moduleSize: 6,
+		expected:   generatorErr("HalftoneImage", "dot ratio must be in range [0.25, 1]"),
+	},
+	{
+		halftone:   Halftone{Picture: testHalftonePicture(4), DotRatio: 1.5},
+		moduleSize: 6,
+		expected:   generatorErr("HalftoneImage", "dot ratio must be in range [0.25, 1]"),
+	},
+}
+
+func Test_HalftoneImageErr(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	for i, data := range HalftoneImageErr_TestData {
+		_, actual := gen.HalftoneImage(data.halftone, 0, data.moduleSize)
+		if actual == nil || actual.Error() != data.expected.Error() {
+			test.Errorf(
+				"halftone.Test_HalftoneImageErr[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+func Test_ditherImage(test *testing.T) {
+	picture := image.NewGray(image.Rect(0, 0, 8, 8))
+	for i := range picture.Pix {
+		picture.Pix[i] = 128
+	}
+	dithered := ditherImage(picture)
+	black := 0
+	for _, v := range dithered.Pix {
+		if v != 0 && v != 255 {
+			test.Fatalf("halftone.Test_ditherImage:\n\tpixel is not black or white -> %d", v)
+		}
+		if v == 0 {
+			black++
+		}
+	}
+	// Mid gray turns into roughly a half of black pixels.
+	if black < 24 || black > 40 {
+		test.Errorf("halftone.Test_ditherImage:\n\tactual black pixels -> %d\n is not close to\n\texpected -> 32", black)
+	}
+}
@@ -0,0 +1,76 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+)
+
+// Default module size of HTML tables in CSS pixels.
+const defaultHtmlModuleSize = 4
+
+// Describes how a QR Code is drawn as an HTML table.
+type HtmlOptions struct {
+	// Width of the quiet zone measured in modules.
+	Margin uint
+
+	// Side of a module in CSS pixels, 4 if zero.
+	ModuleSize uint
+
+	// Colors of black and white modules, black and white if nil.
+	Dark, Light color.Color
+}
+
+// Returns generated QR Code as an HTML table for places where images and SVG
+// are stripped, e.g. email clients. Every row of modules is a table row and
+// every run of modules of the same color is a single cell spanning several
+// columns. All styles are inline, white cells take the background of the table.
+func (gen *Generator) ToHtml(opts HtmlOptions) (string, error) {
+	moduleSize := int(opts.ModuleSize)
+	if moduleSize == 0 {
+		moduleSize = defaultHtmlModuleSize
+	}
+	dark, light := "#000000", "#FFFFFF"
+	if opts.Dark != nil {
+		dark = svgColor(opts.Dark)
+	}
+	if opts.Light != nil {
+		light = svgColor(opts.Light)
+	}
+	if dark == light {
+		return "", generatorErr("ToHtml", "dark and light colors must differ")
+	}
+	modules := gen.GetModules()
+	margin := int(opts.Margin)
+	side := len(modules) + margin*2
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<table cellpadding=\"0\" cellspacing=\"0\" border=\"0\" style=\"border-collapse:collapse;"+
+		"border-spacing:0;width:%dpx;font-size:0;line-height:0;background-color:%s\">\n", side*moduleSize, light)
+	for y := -margin; y < len(modules)+margin; y++ {
+		fmt.Fprintf(&buf, "<tr style=\"height:%dpx\">", moduleSize)
+		for x := -margin; x < len(modules)+margin; {
+			black := matrixModule(modules, x, y)
+			run := 1
+			for x+run < len(modules)+margin && matrixModule(modules, x+run, y) == black {
+				run++
+			}
+			buf.WriteString("<td")
+			if run > 1 {
+				fmt.Fprintf(&buf, " colspan=\"%d\"", run)
+			}
+			fmt.Fprintf(&buf, " style=\"width:%dpx;height:%dpx;padding:0", run*moduleSize, moduleSize)
+			if black {
+				fmt.Fprintf(&buf, ";background-color:%s", dark)
+			}
+			buf.WriteString("\"></td>")
+			x += run
+		}
+		buf.WriteString("</tr>\n")
+	}
+	buf.WriteString("</table>")
+	return buf.String(), nil
+}
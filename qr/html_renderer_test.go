@@ -0,0 +1,95 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"image/color"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var ToHtml_TestData = []struct {
+	opts     HtmlOptions
+	expected string
+}{
+	{
+		opts: HtmlOptions{},
+		expected: "<table cellpadding=\"0\" cellspacing=\"0\" border=\"0\" style=\"border-collapse:collapse;" +
+			"border-spacing:0;width:12px;font-size:0;line-height:0;background-color:#FFFFFF\">\n" +
+			"<tr style=\"height:4px\"><td style=\"width:4px;height:4px;padding:0;background-color:#000000\"></td>" +
+			"<td style=\"width:4px;height:4px;padding:0\"></td>" +
+			"<td style=\"width:4px;height:4px;padding:0;background-color:#000000\"></td></tr>\n" +
+			"<tr style=\"height:4px\"><td style=\"width:4px;height:4px;padding:0\"></td>" +
+			"<td style=\"width:4px;height:4px;padding:0;background-color:#000000\"></td>" +
+			"<td style=\"width:4px;height:4px;padding:0\"></td></tr>\n" +
+			"<tr style=\"height:4px\"><td colspan=\"2\" style=\"width:8px;height:4px;padding:0;background-color:#000000\"></td>" +
+			"<td style=\"width:4px;height:4px;padding:0\"></td></tr>\n" +
+			"</table>",
+	},
+	{
+		opts: HtmlOptions{Margin: 1, ModuleSize: 2, Dark: testDarkBlue, Light: color.RGBA{R: 255, G: 255, B: 224, A: 255}},
+		expected: "<table cellpadding=\"0\" cellspacing=\"0\" border=\"0\" style=\"border-collapse:collapse;" +
+			"border-spacing:0;width:10px;font-size:0;line-height:0;background-color:#FFFFE0\">\n" +
+			"<tr style=\"height:2px\"><td colspan=\"5\" style=\"width:10px;height:2px;padding:0\"></td></tr>\n" +
+			"<tr style=\"height:2px\"><td style=\"width:2px;height:2px;padding:0\"></td>" +
+			"<td style=\"width:2px;height:2px;padding:0;background-color:#000080\"></td>" +
+			"<td style=\"width:2px;height:2px;padding:0\"></td>" +
+			"<td style=\"width:2px;height:2px;padding:0;background-color:#000080\"></td>" +
+			"<td style=\"width:2px;height:2px;padding:0\"></td></tr>\n" +
+			"<tr style=\"height:2px\"><td colspan=\"2\" style=\"width:4px;height:2px;padding:0\"></td>" +
+			"<td style=\"width:2px;height:2px;padding:0;background-color:#000080\"></td>" +
+			"<td colspan=\"2\" style=\"width:4px;height:2px;padding:0\"></td></tr>\n" +
+			"<tr style=\"height:2px\"><td style=\"width:2px;height:2px;padding:0\"></td>" +
+			"<td colspan=\"2\" style=\"width:4px;height:2px;padding:0;background-color:#000080\"></td>" +
+			"<td colspan=\"2\" style=\"width:4px;height:2px;padding:0\"></td></tr>\n" +
+			"<tr style=\"height:2px\"><td colspan=\"5\" style=\"width:10px;height:2px;padding:0\"></td></tr>\n" +
+			"</table>",
+	},
+}
+
+func Test_ToHtml(test *testing.T) {
+	for i, data := range ToHtml_TestData {
+		actual, err := textRendererGen.ToHtml(data.opts)
+		if err != nil || actual != data.expected {
+			test.Errorf(
+				"html_renderer.Test_ToHtml[%d]:\n\tactual -> %s (err: %v)\n is not equal to\n\texpected -> %s",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+	if _, err := textRendererGen.ToHtml(HtmlOptions{Dark: color.White}); err == nil {
+		test.Errorf("html_renderer.Test_ToHtml:\n\tequal colors are accepted")
+	}
+}
+
+func Test_ToHtmlSpans(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	html, err := gen.ToHtml(HtmlOptions{Margin: 4})
+	if err != nil {
+		test.Fatalf("html_renderer.Test_ToHtmlSpans:\n\tunexpected error -> %s", err)
+	}
+	// Every row spans the whole symbol and the quiet zone.
+	cell := regexp.MustCompile(`<td(?: colspan="(\d+)")?`)
+	rows := strings.Split(strings.TrimSuffix(html, "\n</table>"), "\n")[1:]
+	if len(rows) != 29 {
+		test.Fatalf("html_renderer.Test_ToHtmlSpans:\n\tactual rows -> %d\n is not equal to\n\texpected -> 29", len(rows))
+	}
+	for y, row := range rows {
+		columns := 0
+		for _, match := range cell.FindAllStringSubmatch(row, -1) {
+			span := 1
+			if match[1] != "" {
+				span, _ = strconv.Atoi(match[1])
+			}
+			columns += span
+		}
+		if columns != 29 {
+			test.Errorf("html_renderer.Test_ToHtmlSpans:\n\trow %d spans %d columns instead of 29", y, columns)
+		}
+	}
+}
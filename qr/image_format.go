@@ -0,0 +1,204 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Represents a raster image format generated QR Code can be encoded to.
+type ImageFormat int
+
+const (
+	// Portable Network Graphics, lossless.
+	FormatPNG ImageFormat = iota
+
+	// Graphics Interchange Format with a 1-bit black and white palette.
+	FormatGIF
+
+	// JPEG is lossy: compression artifacts around module edges reduce
+	// scanning reliability, so prefer any other format whenever possible.
+	// Images are encoded with the highest quality to keep artifacts minimal.
+	FormatJPEG
+
+	// Windows bitmap with 1 bit per pixel.
+	FormatBMP
+
+	// Netpbm portable bitmap (binary P4), 1 bit per pixel.
+	FormatPBM
+
+	// Netpbm portable graymap (binary P5), 8 bits per pixel.
+	FormatPGM
+)
+
+// Quality used for JPEG encoding.
+const jpegQuality = 100
+
+// File extensions and image formats they are mapped to.
+var imageFormatExtensions = map[string]ImageFormat{
+	".png":  FormatPNG,
+	".gif":  FormatGIF,
+	".jpg":  FormatJPEG,
+	".jpeg": FormatJPEG,
+	".bmp":  FormatBMP,
+	".pbm":  FormatPBM,
+	".pgm":  FormatPGM,
+}
+
+// Returns an image format by the extension of the given file path (case insensitive),
+// PNG if the path has no extension.
+func imageFormatFromPath(path string) (ImageFormat, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" {
+		return FormatPNG, nil
+	}
+	if format, ok := imageFormatExtensions[ext]; ok {
+		return format, nil
+	}
+	return FormatPNG, generatorErr("imageFormatFromPath", fmt.Sprintf("unknown image file extension '%s'", ext))
+}
+
+// Writes img to w in the given format.
+func encodeImage(w io.Writer, img image.Image, format ImageFormat) error {
+	switch format {
+	case FormatPNG:
+		return png.Encode(w, img)
+	case FormatGIF:
+		return gif.Encode(w, toPaletted(img), nil)
+	case FormatJPEG:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	case FormatBMP:
+		return encodeBMP(w, img)
+	case FormatPBM:
+		return encodePBM(w, img)
+	case FormatPGM:
+		return encodePGM(w, img)
+	default:
+		return generatorErr("encodeImage", fmt.Sprintf("unsupported image format %d", format))
+	}
+}
+
+// Returns true if the given color is closer to black than to white.
+func isDarkColor(c color.Color) bool {
+	return color.GrayModel.Convert(c).(color.Gray).Y < 128
+}
+
+// Converts img to a paletted image with a black and white palette.
+func toPaletted(img image.Image) *image.Paletted {
+	bounds := img.Bounds()
+	result := image.NewPaletted(bounds, color.Palette{color.White, color.Black})
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if isDarkColor(img.At(x, y)) {
+				result.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	return result
+}
+
+// Writes img to w as a 1-bit monochrome bottom-up Windows bitmap.
+func encodeBMP(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	rowSize := (width + 31) / 32 * 4
+	const headersSize = 14 + 40 + 2*4
+	header := []interface{}{
+		// BITMAPFILEHEADER
+		[2]byte{'B', 'M'},
+		uint32(headersSize + rowSize*height),
+		uint32(0),
+		uint32(headersSize),
+		// BITMAPINFOHEADER
+		uint32(40),
+		int32(width),
+		int32(height),
+		uint16(1),
+		uint16(1),
+		uint32(0),
+		uint32(rowSize * height),
+		int32(2835),
+		int32(2835),
+		uint32(2),
+		uint32(0),
+		// Palette: index 0 is black, index 1 is white (BGRA).
+		[4]byte{0, 0, 0, 0},
+		[4]byte{255, 255, 255, 0},
+	}
+	bw := bufio.NewWriter(w)
+	for _, field := range header {
+		if err := binary.Write(bw, binary.LittleEndian, field); err != nil {
+			return err
+		}
+	}
+	row := make([]byte, rowSize)
+	for y := bounds.Max.Y - 1; y >= bounds.Min.Y; y-- {
+		for i := range row {
+			row[i] = 0
+		}
+		for x := 0; x < width; x++ {
+			if !isDarkColor(img.At(bounds.Min.X+x, y)) {
+				row[x/8] |= 0x80 >> uint(x%8)
+			}
+		}
+		if _, err := bw.Write(row); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Writes img to w as a binary Netpbm portable bitmap (P4), where 1 is black.
+func encodePBM(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	bw := bufio.NewWriter(w)
+	if _, err := fmt.Fprintf(bw, "P4\n%d %d\n", bounds.Dx(), bounds.Dy()); err != nil {
+		return err
+	}
+	row := make([]byte, (bounds.Dx()+7)/8)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for i := range row {
+			row[i] = 0
+		}
+		for x := 0; x < bounds.Dx(); x++ {
+			if isDarkColor(img.At(bounds.Min.X+x, y)) {
+				row[x/8] |= 0x80 >> uint(x%8)
+			}
+		}
+		if _, err := bw.Write(row); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Writes img to w as a binary Netpbm portable graymap (P5) with 8-bit samples.
+func encodePGM(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	bw := bufio.NewWriter(w)
+	if _, err := fmt.Fprintf(bw, "P5\n%d %d\n255\n", bounds.Dx(), bounds.Dy()); err != nil {
+		return err
+	}
+	row := make([]byte, bounds.Dx())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			row[x] = color.GrayModel.Convert(img.At(bounds.Min.X+x, y)).(color.Gray).Y
+		}
+		if _, err := bw.Write(row); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,143 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+var imageFormatFromPath_TestData = []struct {
+	path     string
+	expected ImageFormat
+	isErr    bool
+}{
+	{path: "qr.png", expected: FormatPNG},
+	{path: "path/to/qr.GIF", expected: FormatGIF},
+	{path: "qr.jpg", expected: FormatJPEG},
+	{path: "qr.jpeg", expected: FormatJPEG},
+	{path: "qr.bmp", expected: FormatBMP},
+	{path: "qr.pbm", expected: FormatPBM},
+	{path: "qr.pgm", expected: FormatPGM},
+	{path: "qr.tiff", expected: FormatPNG, isErr: true},
+	{path: "qr.webp", expected: FormatPNG, isErr: true},
+	{path: "qr", expected: FormatPNG},
+}
+
+func Test_imageFormatFromPath(test *testing.T) {
+	for i, data := range imageFormatFromPath_TestData {
+		actual, err := imageFormatFromPath(data.path)
+		if actual != data.expected || (err != nil) != data.isErr {
+			test.Errorf(
+				"image_format.Test_imageFormatFromPath[%d]:\n\tactual format -> %d (err: %v)\n is not equal to\n\texpected format -> %d",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+}
+
+var EncodeImageDecodable_TestData = []struct {
+	format ImageFormat
+	decode func(*bytes.Buffer) (image.Image, error)
+}{
+	{
+		format: FormatPNG,
+		decode: func(buf *bytes.Buffer) (image.Image, error) { return png.Decode(buf) },
+	},
+	{
+		format: FormatGIF,
+		decode: func(buf *bytes.Buffer) (image.Image, error) { return gif.Decode(buf) },
+	},
+	{
+		format: FormatJPEG,
+		decode: func(buf *bytes.Buffer) (image.Image, error) { return jpeg.Decode(buf) },
+	},
+}
+
+func Test_EncodeImageDecodable(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	for i, data := range EncodeImageDecodable_TestData {
+		var buf bytes.Buffer
+		if err := gen.EncodeImage(&buf, data.format, 4, 116); err != nil {
+			test.Fatalf("image_format.Test_EncodeImageDecodable[%d]:\n\tunexpected error -> %s", i, err)
+		}
+		img, err := data.decode(&buf)
+		if err != nil {
+			test.Fatalf("image_format.Test_EncodeImageDecodable[%d]:\n\tdecoding failed -> %s", i, err)
+		}
+		if img.Bounds().Dx() != 116 || img.Bounds().Dy() != 116 {
+			test.Errorf("image_format.Test_EncodeImageDecodable[%d]:\n\tunexpected bounds -> %v", i, img.Bounds())
+		}
+		// Top left pixel of the finder pattern is black, quiet zone is white.
+		if !isDarkColor(img.At(18, 18)) || isDarkColor(img.At(2, 2)) {
+			test.Errorf("image_format.Test_EncodeImageDecodable[%d]:\n\tunexpected pixel colors", i)
+		}
+	}
+}
+
+var EncodeImageBytes_TestData = []struct {
+	format   ImageFormat
+	expected []byte
+}{
+	{
+		format:   FormatPBM,
+		expected: []byte("P4\n5 5\n\x00\x50\x20\x60\x00"),
+	},
+	{
+		format: FormatPGM,
+		expected: []byte("P5\n5 5\n255\n" +
+			"\xff\xff\xff\xff\xff" +
+			"\xff\x00\xff\x00\xff" +
+			"\xff\xff\x00\xff\xff" +
+			"\xff\x00\x00\xff\xff" +
+			"\xff\xff\xff\xff\xff"),
+	},
+	{
+		format: FormatBMP,
+		expected: append([]byte(
+			"BM\x52\x00\x00\x00\x00\x00\x00\x00\x3e\x00\x00\x00"+
+				"\x28\x00\x00\x00\x05\x00\x00\x00\x05\x00\x00\x00\x01\x00\x01\x00\x00\x00\x00\x00"+
+				"\x14\x00\x00\x00\x13\x0b\x00\x00\x13\x0b\x00\x00\x02\x00\x00\x00\x00\x00\x00\x00"+
+				"\x00\x00\x00\x00\xff\xff\xff\x00"),
+			0xf8, 0, 0, 0,
+			0x98, 0, 0, 0,
+			0xd8, 0, 0, 0,
+			0xa8, 0, 0, 0,
+			0xf8, 0, 0, 0,
+		),
+	},
+}
+
+func Test_EncodeImageBytes(test *testing.T) {
+	for i, data := range EncodeImageBytes_TestData {
+		var buf bytes.Buffer
+		if err := textRendererGen.EncodeImage(&buf, data.format, 1, 5); err != nil {
+			test.Fatalf("image_format.Test_EncodeImageBytes[%d]:\n\tunexpected error -> %s", i, err)
+		}
+		if !bytes.Equal(buf.Bytes(), data.expected) {
+			test.Errorf(
+				"image_format.Test_EncodeImageBytes[%d]:\n\tactual bytes -> %q\n is not equal to\n\texpected bytes -> %q",
+				i, buf.Bytes(), data.expected,
+			)
+		}
+	}
+}
+
+func Test_EncodeImageErr(test *testing.T) {
+	var buf bytes.Buffer
+	expected := generatorErr("EncodeImage", "size of code is less than minimum size > 5x5")
+	if actual := textRendererGen.EncodeImage(&buf, FormatPNG, 1, 4); actual == nil || actual.Error() != expected.Error() {
+		test.Errorf("image_format.Test_EncodeImageErr:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v", actual, expected)
+	}
+	expected = generatorErr("encodeImage", "unsupported image format 42")
+	if actual := textRendererGen.EncodeImage(&buf, ImageFormat(42), 1, 5); actual == nil || actual.Error() != expected.Error() {
+		test.Errorf("image_format.Test_EncodeImageErr:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v", actual, expected)
+	}
+}
@@ -0,0 +1,108 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// Names of module types in the JSON export, indexed by the digits of its type map.
+var jsonModuleTypes = []string{"finder", "timing", "alignment", "format", "version", "data", "ecc"}
+
+// Indices of module types in jsonModuleTypes.
+const (
+	jsonFinder = iota
+	jsonTiming
+	jsonAlignment
+	jsonFormat
+	jsonVersion
+	jsonData
+	jsonEcc
+)
+
+// Indices of JSON module types of module roles.
+var jsonRoleTypes = map[ModuleRole]int{
+	RoleFinder:      jsonFinder,
+	RoleSeparator:   jsonFinder,
+	RoleTiming:      jsonTiming,
+	RoleAlignment:   jsonAlignment,
+	RoleFormatInfo:  jsonFormat,
+	RoleVersionInfo: jsonVersion,
+	RoleDarkModule:  jsonFormat,
+	RoleData:        jsonData,
+	RoleEcc:         jsonEcc,
+	RoleRemainder:   jsonData,
+}
+
+// Letters of error correction levels.
+var eccNames = [4]string{"L", "M", "Q", "H"}
+
+// JSON representation of a QR Code symbol.
+type jsonSymbol struct {
+	Version int    `json:"version"`
+	Size    int    `json:"size"`
+	Ecc     string `json:"ecc"`
+	Mask    int    `json:"mask"`
+
+	// Base64 encoded modules in row-major order, packed 8 per byte with
+	// the most significant bit first, 1 is black. The last byte is padded with zeros.
+	Modules string `json:"modules"`
+
+	// Names of module types.
+	Types []string `json:"types"`
+
+	// Module types in row-major order, one digit per module, indexing Types.
+	TypeMap string `json:"typeMap"`
+}
+
+// Returns generated QR Code as JSON for front-end renderers, e.g. with <canvas>:
+//
+//	{
+//		"version": 1, "size": 21, "ecc": "M", "mask": 2,
+//		"modules": "/sB...", "types": ["finder", ...], "typeMap": "0000000310..."
+//	}
+//
+// Modules are packed into a base64 encoded bit string in row-major order with
+// the most significant bit of every byte first, 1 is black. Every character of
+// the type map is the index of the type of the corresponding module in types:
+// finder (including separators), timing, alignment, format (including the dark
+// module), version, data (including remainder bits) and ecc.
+func (gen *Generator) ToJson() (string, error) {
+	packed := make([]byte, (gen.size*gen.size+7)/8)
+	typeMap := make([]byte, 0, gen.size*gen.size)
+	types := gen.jsonTypes()
+	for y := 0; y < gen.size; y++ {
+		for x := 0; x < gen.size; x++ {
+			if i := y*gen.size + x; gen.module(x, y) {
+				packed[i/8] |= 0x80 >> uint(i%8)
+			}
+			typeMap = append(typeMap, byte('0'+types[y][x]))
+		}
+	}
+	data, err := json.Marshal(jsonSymbol{
+		Version: gen.version,
+		Size:    gen.size,
+		Ecc:     eccNames[gen.errorCorrectionLevel],
+		Mask:    gen.mask,
+		Modules: base64.StdEncoding.EncodeToString(packed),
+		Types:   jsonModuleTypes,
+		TypeMap: string(typeMap),
+	})
+	return string(data), err
+}
+
+// Returns the index of the JSON type of every module of the symbol.
+func (gen *Generator) jsonTypes() [][]int {
+	roles := gen.ModuleRoles()
+	types := make([][]int, gen.size)
+	for y := range types {
+		types[y] = make([]int, gen.size)
+		for x := range types[y] {
+			types[y][x] = jsonRoleTypes[roles[y][x].Role]
+		}
+	}
+	return types
+}
@@ -0,0 +1,84 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var ToJson_TestData = []struct {
+	minVersion int
+	ecl        eccType
+	expected   jsonSymbol
+
+	// Expected number of modules of every type.
+	counts [7]int
+}{
+	{
+		minVersion: 1,
+		ecl:        eccQUARTILE,
+		expected:   jsonSymbol{Version: 1, Size: 21, Ecc: "Q"},
+		counts:     [7]int{192, 10, 0, 31, 0, 104, 104},
+	},
+	{
+		minVersion: 7,
+		ecl:        eccMEDIUM,
+		expected:   jsonSymbol{Version: 7, Size: 45, Ecc: "M"},
+		counts:     [7]int{192, 58, 140, 31, 36, 992, 576},
+	},
+}
+
+func Test_ToJson(test *testing.T) {
+	for i, data := range ToJson_TestData {
+		segs, _ := makeSegments("HELLO WORLD")
+		gen := Generator{}
+		gen = gen.encodeSegments(&segs, data.ecl, data.minVersion, 40, -1, false)
+		s, err := gen.ToJson()
+		if err != nil {
+			test.Fatalf("json_export.Test_ToJson[%d]:\n\tunexpected error -> %s", i, err)
+		}
+		var actual jsonSymbol
+		if err := json.Unmarshal([]byte(s), &actual); err != nil {
+			test.Fatalf("json_export.Test_ToJson[%d]:\n\tunmarshalling failed -> %s", i, err)
+		}
+		if actual.Version != data.expected.Version || actual.Size != data.expected.Size ||
+			actual.Ecc != data.expected.Ecc || actual.Mask != gen.mask {
+			test.Errorf(
+				"json_export.Test_ToJson[%d]:\n\tactual header -> %d %d %s %d\n is not equal to\n\texpected -> %d %d %s %d",
+				i, actual.Version, actual.Size, actual.Ecc, actual.Mask,
+				data.expected.Version, data.expected.Size, data.expected.Ecc, gen.mask,
+			)
+		}
+		if !reflect.DeepEqual(actual.Types, jsonModuleTypes) {
+			test.Errorf("json_export.Test_ToJson[%d]:\n\tactual types -> %v", i, actual.Types)
+		}
+		packed, err := base64.StdEncoding.DecodeString(actual.Modules)
+		if err != nil {
+			test.Fatalf("json_export.Test_ToJson[%d]:\n\tbase64 decoding failed -> %s", i, err)
+		}
+		modules := gen.GetModules()
+		for y := range modules {
+			for x := range modules[y] {
+				bit := y*gen.size + x
+				if (packed[bit/8]>>uint(7-bit%8))&1 == 1 != modules[y][x] {
+					test.Fatalf("json_export.Test_ToJson[%d]:\n\tmodule (%d, %d) is packed wrongly", i, x, y)
+				}
+			}
+		}
+		var counts [7]int
+		for _, c := range actual.TypeMap {
+			counts[c-'0']++
+		}
+		if counts != data.counts {
+			test.Errorf(
+				"json_export.Test_ToJson[%d]:\n\tactual type counts -> %v\n is not equal to\n\texpected -> %v",
+				i, counts, data.counts,
+			)
+		}
+	}
+}
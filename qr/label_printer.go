@@ -0,0 +1,78 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Largest width in bytes and height in dots of an ESC/POS raster bit image.
+const maxEscPosRaster = 0xffff
+
+// Writes generated QR Code to w as a ZPL graphic field (^GF) in the
+// ASCII hexadecimal format, terminated with ^FS. Every module is moduleSize x
+// moduleSize printer dots, margin is measured in modules. Position the field
+// with a ^FO command written before it, e.g. "^XA^FO50,50" + field + "^XZ".
+func (gen *Generator) DrawZpl(w io.Writer, margin, moduleSize uint) error {
+	rows, err := gen.rasterRows("DrawZpl", margin, moduleSize)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	total := len(rows) * len(rows[0])
+	fmt.Fprintf(&buf, "^GFA,%d,%d,%d,", total, total, len(rows[0]))
+	for _, row := range rows {
+		fmt.Fprintf(&buf, "%X", row)
+	}
+	buf.WriteString("^FS")
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// Writes generated QR Code to w as an ESC/POS raster bit image command (GS v 0)
+// in the normal density mode. Every module is moduleSize x moduleSize printer
+// dots, margin is measured in modules.
+func (gen *Generator) DrawEscPos(w io.Writer, margin, moduleSize uint) error {
+	rows, err := gen.rasterRows("DrawEscPos", margin, moduleSize)
+	if err != nil {
+		return err
+	}
+	width, height := len(rows[0]), len(rows)
+	if width > maxEscPosRaster || height > maxEscPosRaster {
+		return generatorErr("DrawEscPos", "image is too large for a raster bit image")
+	}
+	var buf bytes.Buffer
+	buf.Write([]byte{0x1d, 'v', '0', 0, byte(width), byte(width >> 8), byte(height), byte(height >> 8)})
+	for _, row := range rows {
+		buf.Write(row)
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// Returns rows of printer dots of generated QR Code, every row is packed into
+// bytes with the most significant bit first, black dots are set bits and
+// rows are padded with white dots up to a whole number of bytes.
+//
+// Helper method for label printer renderers.
+func (gen *Generator) rasterRows(method string, margin, moduleSize uint) ([][]byte, error) {
+	if moduleSize == 0 {
+		return nil, generatorErr(method, "module size must be positive")
+	}
+	modules := gen.GetModules()
+	side := (len(modules) + int(margin)*2) * int(moduleSize)
+	rows := make([][]byte, side)
+	for y := range rows {
+		rows[y] = make([]byte, (side+7)/8)
+		for x := 0; x < side; x++ {
+			if matrixModule(modules, x/int(moduleSize)-int(margin), y/int(moduleSize)-int(margin)) {
+				rows[y][x/8] |= 0x80 >> uint(x%8)
+			}
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,71 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"bytes"
+	"testing"
+)
+
+var DrawZpl_TestData = []struct {
+	margin, moduleSize uint
+	expected           string
+}{
+	{margin: 0, moduleSize: 1, expected: "^GFA,3,3,1,A040C0^FS"},
+	{margin: 1, moduleSize: 1, expected: "^GFA,5,5,1,0050206000^FS"},
+	{margin: 0, moduleSize: 3, expected: "^GFA,18,18,2,E380E380E3801C001C001C00FC00FC00FC00^FS"},
+}
+
+func Test_DrawZpl(test *testing.T) {
+	for i, data := range DrawZpl_TestData {
+		var buf bytes.Buffer
+		err := textRendererGen.DrawZpl(&buf, data.margin, data.moduleSize)
+		if actual := buf.String(); err != nil || actual != data.expected {
+			test.Errorf(
+				"label_printer.Test_DrawZpl[%d]:\n\tactual -> %s (err: %v)\n is not equal to\n\texpected -> %s",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+	if err := textRendererGen.DrawZpl(&bytes.Buffer{}, 0, 0); err == nil {
+		test.Errorf("label_printer.Test_DrawZpl:\n\tzero module size is accepted")
+	}
+}
+
+var DrawEscPos_TestData = []struct {
+	margin, moduleSize uint
+	expected           []byte
+}{
+	{
+		margin: 0, moduleSize: 1,
+		expected: []byte{0x1d, 0x76, 0x30, 0x00, 0x01, 0x00, 0x03, 0x00, 0xa0, 0x40, 0xc0},
+	},
+	{
+		margin: 2, moduleSize: 2,
+		expected: append([]byte{0x1d, 0x76, 0x30, 0x00, 0x02, 0x00, 0x0e, 0x00},
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x0c, 0xc0, 0x0c, 0xc0,
+			0x03, 0x00, 0x03, 0x00,
+			0x0f, 0x00, 0x0f, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		),
+	},
+}
+
+func Test_DrawEscPos(test *testing.T) {
+	for i, data := range DrawEscPos_TestData {
+		var buf bytes.Buffer
+		err := textRendererGen.DrawEscPos(&buf, data.margin, data.moduleSize)
+		if actual := buf.Bytes(); err != nil || !bytes.Equal(actual, data.expected) {
+			test.Errorf(
+				"label_printer.Test_DrawEscPos[%d]:\n\tactual -> % x (err: %v)\n is not equal to\n\texpected -> % x",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+	if err := textRendererGen.DrawEscPos(&bytes.Buffer{}, 0, 0); err == nil {
+		test.Errorf("label_printer.Test_DrawEscPos:\n\tzero module size is accepted")
+	}
+}
@@ -0,0 +1,95 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import "image"
+
+// Describes where an interleaved codeword of a QR Code symbol comes from.
+type codewordSource struct {
+	// Index of the error correction block.
+	block int
+
+	// Index of the codeword within the block, data codewords go first.
+	index int
+
+	// Indicates error correction codewords.
+	isEcc bool
+}
+
+// Returns coordinates of all non-function modules in the order codeword bits
+// are placed onto them: two-module wide columns zigzagging upwards and downwards
+// from the right edge of the symbol, skipping the vertical timing pattern.
+// The trailing modules which do not receive a codeword bit are remainder bits.
+//
+// Function modules need to be marked off before this is called.
+func (gen *Generator) dataModuleOrder() []image.Point {
+	var order []image.Point
+	for right := gen.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < gen.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if ((right + 1) & 2) == 0 {
+					y = gen.size - 1 - vert
+				}
+				if !gen.isFunction[y][x] {
+					order = append(order, image.Point{X: x, Y: y})
+				}
+			}
+		}
+	}
+	return order
+}
+
+// Returns the source of every codeword in the interleaved sequence produced by
+// appendErrorCorrection, based on this object's version and error correction level.
+func (gen *Generator) codewordSources() []codewordSource {
+	numBlocks := numErrorCorrectionBlocks[int(gen.errorCorrectionLevel)][gen.version]
+	blockEccLen := eccCodewordsPerBlock[int(gen.errorCorrectionLevel)][gen.version]
+	rawCodewords := gen.getNumRawDataModules(gen.version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+	var result []codewordSource
+	for i := 0; i <= shortBlockLen; i++ {
+		for j := 0; j < numBlocks; j++ {
+			if i == shortBlockLen-blockEccLen && j < numShortBlocks {
+				continue
+			}
+			index := i
+			if j < numShortBlocks && i > shortBlockLen-blockEccLen {
+				index--
+			}
+			dataLen := shortBlockLen - blockEccLen
+			if j >= numShortBlocks {
+				dataLen++
+			}
+			result = append(result, codewordSource{block: j, index: index, isEcc: index >= dataLen})
+		}
+	}
+	return result
+}
+
+// Returns the number of erroneous codewords a single error correction block
+// can correct at this object's version and error correction level. Small
+// symbols reserve some error correction codewords for misdecode protection.
+func (gen *Generator) correctableCodewords() int {
+	misdecode := 0
+	switch gen.version {
+	case 1:
+		misdecode = [4]int{3, 2, 1, 1}[gen.errorCorrectionLevel]
+	case 2:
+		if gen.errorCorrectionLevel == eccLOW {
+			misdecode = 2
+		}
+	case 3:
+		if gen.errorCorrectionLevel == eccLOW {
+			misdecode = 1
+		}
+	}
+	return (eccCodewordsPerBlock[int(gen.errorCorrectionLevel)][gen.version] - misdecode) / 2
+}
@@ -0,0 +1,123 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import "testing"
+
+var dataModuleOrder_TestData = []struct {
+	text     string
+	expected int
+}{
+	{text: "HELLO WORLD", expected: 208},
+	{text: "Nibh. Pulvinar enim porttitor tellus litora nec vestibulum sit montes class", expected: 807},
+}
+
+func Test_dataModuleOrder(test *testing.T) {
+	for i, data := range dataModuleOrder_TestData {
+		gen := Generator{}
+		gen = gen.EncodeText(data.text)
+		order := gen.dataModuleOrder()
+		if len(order) != data.expected || len(order) != gen.getNumRawDataModules(gen.version) {
+			test.Errorf(
+				"layout.Test_dataModuleOrder[%d]:\n\tactual length -> %d\n is not equal to\n\texpected length -> %d",
+				i, len(order), data.expected,
+			)
+		}
+		// Placement starts at the bottom right corner.
+		if order[0].X != gen.size-1 || order[0].Y != gen.size-1 || order[1].X != gen.size-2 {
+			test.Errorf("layout.Test_dataModuleOrder[%d]:\n\tinvalid first modules -> %v, %v", i, order[0], order[1])
+		}
+		for _, p := range order {
+			if gen.isFunction[p.Y][p.X] {
+				test.Errorf("layout.Test_dataModuleOrder[%d]:\n\tfunction module in order -> %v", i, p)
+			}
+		}
+	}
+}
+
+var codewordSources_TestData = []struct {
+	version int
+	ecl     eccType
+}{
+	{version: 1, ecl: eccLOW},
+	{version: 5, ecl: eccQUARTILE},
+	{version: 13, ecl: eccHIGH},
+	{version: 40, ecl: eccMEDIUM},
+}
+
+func Test_codewordSources(test *testing.T) {
+	for i, data := range codewordSources_TestData {
+		gen := Generator{version: data.version, errorCorrectionLevel: data.ecl}
+		dataLen := gen.getNumDataCodewords(data.version, data.ecl)
+		codewords := make([]uint8, dataLen)
+		for k := range codewords {
+			codewords[k] = uint8(k)
+		}
+		interleaved := gen.appendErrorCorrection(codewords)
+		sources := gen.codewordSources()
+		if len(sources) != len(interleaved) {
+			test.Fatalf(
+				"layout.Test_codewordSources[%d]:\n\tactual length -> %d\n is not equal to\n\texpected length -> %d",
+				i, len(sources), len(interleaved),
+			)
+		}
+		// Offsets of the data codewords of every block in the non-interleaved sequence.
+		numBlocks := numErrorCorrectionBlocks[data.ecl][data.version]
+		blockLen := gen.getNumRawDataModules(data.version)/8/numBlocks - eccCodewordsPerBlock[data.ecl][data.version]
+		numShortBlocks := numBlocks - gen.getNumRawDataModules(data.version)/8%numBlocks
+		offsets := make([]int, numBlocks)
+		for block := 1; block < numBlocks; block++ {
+			offsets[block] = offsets[block-1] + blockLen
+			if block-1 >= numShortBlocks {
+				offsets[block]++
+			}
+		}
+		numEcc := 0
+		for _, source := range sources {
+			if source.isEcc {
+				numEcc++
+			}
+		}
+		expectedEcc := eccCodewordsPerBlock[data.ecl][data.version] * numErrorCorrectionBlocks[data.ecl][data.version]
+		if numEcc != expectedEcc {
+			test.Errorf("layout.Test_codewordSources[%d]:\n\tactual ecc codewords -> %d\n is not equal to\n\texpected -> %d", i, numEcc, expectedEcc)
+		}
+		for k, source := range sources {
+			if source.isEcc {
+				continue
+			}
+			if expected := uint8(offsets[source.block] + source.index); interleaved[k] != expected {
+				test.Errorf(
+					"layout.Test_codewordSources[%d]:\n\tactual codeword %d -> %d\n is not equal to\n\texpected -> %d",
+					i, k, interleaved[k], expected,
+				)
+			}
+		}
+	}
+}
+
+var correctableCodewords_TestData = []struct {
+	version  int
+	ecl      eccType
+	expected int
+}{
+	{version: 1, ecl: eccLOW, expected: 2},
+	{version: 1, ecl: eccHIGH, expected: 8},
+	{version: 2, ecl: eccLOW, expected: 4},
+	{version: 3, ecl: eccLOW, expected: 7},
+	{version: 10, ecl: eccQUARTILE, expected: 12},
+}
+
+func Test_correctableCodewords(test *testing.T) {
+	for i, data := range correctableCodewords_TestData {
+		gen := Generator{version: data.version, errorCorrectionLevel: data.ecl}
+		if actual := gen.correctableCodewords(); actual != data.expected {
+			test.Errorf(
+				"layout.Test_correctableCodewords[%d]:\n\tactual -> %d\n is not equal to\n\texpected -> %d",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"fmt"
+	"image"
+)
+
+// Describes a logo placed in the center of a QR Code. Modules under the logo
+// are cleared and have to be recovered by error correction, so the covered
+// area is checked against the correctable budget of every block.
+//
+// Function modules under the logo (alignment and timing patterns) are never
+// cleared and are drawn on top of it. A logo that reaches finder patterns,
+// format or version information is refused.
+type Logo struct {
+	// Logo drawn by raster renderers. It is also embedded into SVG output
+	// as a PNG data URI if Href is empty.
+	Image image.Image
+
+	// URL or data URI of the logo referenced by SVG output,
+	// e.g. a nested SVG image ("data:image/svg+xml,...").
+	Href string
+
+	// Side of the cleared area as a fraction of the symbol side, in the range (0, 1).
+	Size float64
+}
+
+// Returns a QR Code symbol encoding the same data as this one, with the lowest
+// error correction level not less than the current one at which the given logo
+// stays within the correctable budget. The version may grow.
+func (gen *Generator) FitLogo(logo Logo) (Generator, error) {
+	if len(gen.segments) == 0 {
+		return Generator{}, generatorErr("FitLogo", "QR Code was not encoded")
+	}
+	var err error
+	for ecl := gen.errorCorrectionLevel; ecl <= eccHIGH; ecl++ {
+		candidate := *gen
+		if ecl != gen.errorCorrectionLevel {
+			if bits, _ := getTotalBits(&gen.segments, maxVersion); bits == -1 || bits > gen.getNumDataCodewords(maxVersion, ecl)*8 {
+				break
+			}
+			candidate = gen.encodeSegments(&gen.segments, ecl, minVersion, maxVersion, -1, false)
+			candidate.fill = gen.fill
+		}
+		if _, err = candidate.logoArea("FitLogo", logo); err == nil {
+			return candidate, nil
+		}
+	}
+	return Generator{}, err
+}
+
+// Returns the area in modules cleared by the given logo. Returns an error if
+// the logo covers finder patterns, format or version information, or more
+// codewords of any block than error correction can recover.
+func (gen *Generator) logoArea(method string, logo Logo) (image.Rectangle, error) {
+	if logo.Size <= 0 || logo.Size >= 1 {
+		return image.Rectangle{}, generatorErr(method, "logo size must be in range (0, 1)")
+	}
+	side := int(logo.Size * float64(gen.size))
+	if (gen.size-side)%2 != 0 {
+		side++
+	}
+	start := (gen.size - side) / 2
+	area := image.Rect(start, start, start+side, start+side)
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			if gen.isInfoModule(x, y) {
+				return image.Rectangle{}, generatorErr(method, "logo covers finder patterns, format or version information")
+			}
+		}
+	}
+	covered := map[int]bool{}
+	order := gen.dataModuleOrder()
+	numCodewords := gen.getNumRawDataModules(gen.version) / 8
+	for i, p := range order {
+		if i < numCodewords*8 && p.In(area) {
+			covered[i/8] = true
+		}
+	}
+	perBlock := make([]int, numErrorCorrectionBlocks[int(gen.errorCorrectionLevel)][gen.version])
+	sources := gen.codewordSources()
+	for codeword := range covered {
+		perBlock[sources[codeword].block]++
+	}
+	limit := gen.correctableCodewords()
+	for block, count := range perBlock {
+		if count > limit {
+			return image.Rectangle{}, generatorErr(method, fmt.Sprintf(
+				"logo covers %d codewords of block %d, only %d can be corrected", count, block, limit,
+			))
+		}
+	}
+	return area, nil
+}
+
+// Returns true if the module at (x, y) belongs to a finder pattern, its separator,
+// format information or version information.
+func (gen *Generator) isInfoModule(x, y int) bool {
+	near, far := 9, gen.size-8
+	if gen.version >= 7 {
+		// Version information blocks are adjacent to the top right and bottom left separators.
+		if (x >= gen.size-11 && y < 6) || (x < 6 && y >= gen.size-11) {
+			return true
+		}
+	}
+	return (x < near && y < near) || (x >= far && y < near) || (x < near && y >= far)
+}
+
+// Returns the logo as a PNG data URI to be embedded into SVG output.
+func (logo Logo) svgHref() (string, error) {
+	if logo.Href != "" {
+		return logo.Href, nil
+	}
+	if logo.Image == nil {
+		return "", generatorErr("svgHref", "logo has neither an image nor a reference")
+	}
+	return ImageDataUri(logo.Image, FormatPNG)
+}
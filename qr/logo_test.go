@@ -0,0 +1,132 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+const logoTestText = "https://github.com/YuriyLisovskiy"
+
+// Returns a solid red square image.
+func testLogoImage(side int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, side, side))
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	return img
+}
+
+var logoArea_TestData = []struct {
+	size     float64
+	expected image.Rectangle
+	isErr    bool
+}{
+	{size: 0.2, expected: image.Rect(15, 15, 22, 22)},
+	{size: 0.25, expected: image.Rect(14, 14, 23, 23)},
+	{size: 0, isErr: true},
+	{size: 1, isErr: true},
+	{size: 0.7, isErr: true},
+}
+
+func Test_logoArea(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText(logoTestText)
+	// 37x37 symbol at high error correction level.
+	gen = gen.encodeSegments(&gen.segments, eccHIGH, 5, 5, -1, false)
+	for i, data := range logoArea_TestData {
+		actual, err := gen.logoArea("logoArea", Logo{Size: data.size})
+		if actual != data.expected || (err != nil) != data.isErr {
+			test.Errorf(
+				"logo.Test_logoArea[%d]:\n\tactual area -> %v (err: %v)\n is not equal to\n\texpected area -> %v",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+}
+
+func Test_logoAreaBudget(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText(logoTestText)
+	low := gen.encodeSegments(&gen.segments, eccLOW, 5, 5, -1, false)
+	high := gen.encodeSegments(&gen.segments, eccHIGH, 5, 5, -1, false)
+	logo := Logo{Size: 0.3}
+	if _, err := low.logoArea("logoArea", logo); err == nil || !strings.Contains(err.Error(), "can be corrected") {
+		test.Errorf("logo.Test_logoAreaBudget:\n\tlogo is accepted at low error correction level (err: %v)", err)
+	}
+	if _, err := high.logoArea("logoArea", logo); err != nil {
+		test.Errorf("logo.Test_logoAreaBudget:\n\tlogo is refused at high error correction level -> %s", err)
+	}
+}
+
+func Test_FitLogo(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText(logoTestText)
+	gen = gen.encodeSegments(&gen.segments, eccLOW, 1, 40, -1, false)
+	logo := Logo{Size: 0.3}
+	actual, err := gen.FitLogo(logo)
+	if err != nil {
+		test.Fatalf("logo.Test_FitLogo:\n\tunexpected error -> %s", err)
+	}
+	if actual.errorCorrectionLevel <= gen.errorCorrectionLevel {
+		test.Errorf("logo.Test_FitLogo:\n\terror correction level was not raised -> %d", actual.errorCorrectionLevel)
+	}
+	if _, err = actual.logoArea("FitLogo", logo); err != nil {
+		test.Errorf("logo.Test_FitLogo:\n\tresult does not fit the logo -> %s", err)
+	}
+	if _, err = gen.FitLogo(Logo{Size: 0.6}); err == nil {
+		test.Errorf("logo.Test_FitLogo:\n\toversized logo is accepted")
+	}
+	if _, err = (&Generator{}).FitLogo(logo); err == nil {
+		test.Errorf("logo.Test_FitLogo:\n\tempty generator is accepted")
+	}
+}
+
+func Test_StyledImageLogo(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText(logoTestText)
+	gen = gen.encodeSegments(&gen.segments, eccHIGH, 5, 5, -1, false)
+	logo := &Logo{Image: testLogoImage(10), Size: 0.25}
+	img, err := gen.StyledImage(Style{Logo: logo}, 0, 4)
+	if err != nil {
+		test.Fatalf("logo.Test_StyledImageLogo:\n\tunexpected error -> %s", err)
+	}
+	// Center of the logo area is covered by the logo except for function modules.
+	for y := 14; y < 23; y++ {
+		for x := 14; x < 23; x++ {
+			r, g, b, _ := img.At(x*4+2, y*4+2).RGBA()
+			isRed := r == 0xffff && g == 0 && b == 0
+			if gen.isFunction[y][x] == isRed {
+				test.Errorf("logo.Test_StyledImageLogo:\n\tunexpected color of module (%d, %d) -> %v", x, y, img.At(x*4+2, y*4+2))
+			}
+		}
+	}
+	if _, err = gen.StyledImage(Style{Logo: &Logo{Size: 0.25}}, 0, 4); err == nil {
+		test.Errorf("logo.Test_StyledImageLogo:\n\tlogo without image is accepted")
+	}
+}
+
+func Test_ToStyledSvgLogo(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText(logoTestText)
+	gen = gen.encodeSegments(&gen.segments, eccHIGH, 5, 5, -1, false)
+	actual, err := gen.ToStyledSvg(Style{Logo: &Logo{Href: "logo.svg?a=1&b=2", Size: 0.25}}, 2)
+	if err != nil {
+		test.Fatalf("logo.Test_ToStyledSvgLogo:\n\tunexpected error -> %s", err)
+	}
+	expected := "<image x=\"16\" y=\"16\" width=\"9\" height=\"9\" preserveAspectRatio=\"xMidYMid meet\" xlink:href=\"logo.svg?a=1&amp;b=2\"/>"
+	if !strings.Contains(actual, expected) {
+		test.Errorf("logo.Test_ToStyledSvgLogo:\n\tsvg -> %s\n does not contain\n\texpected -> %s", actual, expected)
+	}
+	actual, err = gen.ToStyledSvg(Style{Logo: &Logo{Image: testLogoImage(2), Size: 0.25}}, 2)
+	if err != nil || !strings.Contains(actual, "xlink:href=\"data:image/png;base64,") {
+		test.Errorf("logo.Test_ToStyledSvgLogo:\n\timage is not embedded as data URI (err: %v)", err)
+	}
+}
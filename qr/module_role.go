@@ -0,0 +1,114 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import "fmt"
+
+// Represents the purpose of a module in a QR Code symbol.
+type ModuleRole int
+
+const (
+	// 7x7 finder pattern in three corners of the symbol.
+	RoleFinder ModuleRole = iota
+
+	// White separator between a finder pattern and the encoding region.
+	RoleSeparator
+
+	// Alternating timing pattern in the 6th row and column.
+	RoleTiming
+
+	// 5x5 alignment pattern.
+	RoleAlignment
+
+	// Format information: error correction level and mask.
+	RoleFormatInfo
+
+	// Version information of symbols of version 7 and larger.
+	RoleVersionInfo
+
+	// Single always black module next to the lower left format information.
+	RoleDarkModule
+
+	// Bit of a data codeword.
+	RoleData
+
+	// Bit of an error correction codeword.
+	RoleEcc
+
+	// Remainder bit left over after placing all codewords.
+	RoleRemainder
+)
+
+// Names of module roles.
+var moduleRoleNames = []string{
+	"finder", "separator", "timing", "alignment", "format info",
+	"version info", "dark module", "data", "ecc", "remainder",
+}
+
+func (r ModuleRole) String() string {
+	if r < 0 || int(r) >= len(moduleRoleNames) {
+		return fmt.Sprintf("ModuleRole(%d)", r)
+	}
+	return moduleRoleNames[r]
+}
+
+// Describes a module of a QR Code symbol.
+type ModuleInfo struct {
+	Role ModuleRole
+
+	// For data and error correction codeword bits: index of the codeword in the
+	// final interleaved sequence, index of its error correction block, index of
+	// the codeword within that block (data codewords go first) and index of the
+	// bit within the codeword, 7 being the most significant one. All of them
+	// are -1 for other modules.
+	Codeword, Block, BlockCodeword, Bit int
+}
+
+// Returns the role of every module of generated QR Code. Rows and columns
+// are indexed the same way as in GetModules.
+func (gen *Generator) ModuleRoles() [][]ModuleInfo {
+	roles := make([][]ModuleInfo, gen.size)
+	for y := range roles {
+		roles[y] = make([]ModuleInfo, gen.size)
+		for x := range roles[y] {
+			roles[y][x] = ModuleInfo{Role: gen.functionRole(x, y), Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}
+		}
+	}
+	sources := gen.codewordSources()
+	for i, p := range gen.dataModuleOrder() {
+		if i/8 >= len(sources) {
+			roles[p.Y][p.X].Role = RoleRemainder
+			continue
+		}
+		source := sources[i/8]
+		role := RoleData
+		if source.isEcc {
+			role = RoleEcc
+		}
+		roles[p.Y][p.X] = ModuleInfo{Role: role, Codeword: i / 8, Block: source.block, BlockCodeword: source.index, Bit: 7 - i%8}
+	}
+	return roles
+}
+
+// Returns the role of the function module at the given coordinates,
+// the result is meaningless for other modules.
+func (gen *Generator) functionRole(x, y int) ModuleRole {
+	far := gen.size - 8
+	switch {
+	case x < 7 && y < 7, x > far && y < 7, x < 7 && y > far:
+		return RoleFinder
+	case x < 8 && y < 8, x >= far && y < 8, x < 8 && y >= far:
+		return RoleSeparator
+	case x == 6 || y == 6:
+		return RoleTiming
+	case x == 8 && y == far:
+		return RoleDarkModule
+	case x == 8 && (y <= 8 || y >= far), y == 8 && (x <= 8 || x >= far):
+		return RoleFormatInfo
+	case x < 6 && y >= far-3, y < 6 && x >= far-3:
+		return RoleVersionInfo
+	}
+	return RoleAlignment
+}
@@ -0,0 +1,93 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import "testing"
+
+var ModuleRoles_TestData = []struct {
+	x, y     int
+	expected ModuleInfo
+}{
+	{x: 6, y: 6, expected: ModuleInfo{Role: RoleFinder, Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}},
+	{x: 7, y: 7, expected: ModuleInfo{Role: RoleSeparator, Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}},
+	{x: 37, y: 0, expected: ModuleInfo{Role: RoleSeparator, Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}},
+	{x: 38, y: 0, expected: ModuleInfo{Role: RoleFinder, Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}},
+	{x: 6, y: 8, expected: ModuleInfo{Role: RoleTiming, Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}},
+	{x: 8, y: 6, expected: ModuleInfo{Role: RoleTiming, Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}},
+	{x: 8, y: 0, expected: ModuleInfo{Role: RoleFormatInfo, Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}},
+	{x: 44, y: 8, expected: ModuleInfo{Role: RoleFormatInfo, Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}},
+	{x: 8, y: 37, expected: ModuleInfo{Role: RoleDarkModule, Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}},
+	{x: 34, y: 5, expected: ModuleInfo{Role: RoleVersionInfo, Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}},
+	{x: 0, y: 36, expected: ModuleInfo{Role: RoleVersionInfo, Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}},
+	{x: 8, y: 22, expected: ModuleInfo{Role: RoleAlignment, Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}},
+	{x: 22, y: 22, expected: ModuleInfo{Role: RoleAlignment, Codeword: -1, Block: -1, BlockCodeword: -1, Bit: -1}},
+	{x: 44, y: 44, expected: ModuleInfo{Role: RoleData, Codeword: 0, Block: 0, BlockCodeword: 0, Bit: 7}},
+	{x: 43, y: 44, expected: ModuleInfo{Role: RoleData, Codeword: 0, Block: 0, BlockCodeword: 0, Bit: 6}},
+	{x: 44, y: 40, expected: ModuleInfo{Role: RoleData, Codeword: 1, Block: 1, BlockCodeword: 0, Bit: 7}},
+	{x: 0, y: 29, expected: ModuleInfo{Role: RoleEcc, Codeword: 194, Block: 0, BlockCodeword: 97, Bit: 0}},
+}
+
+func Test_ModuleRoles(test *testing.T) {
+	segs, _ := makeSegments("HELLO WORLD")
+	gen := Generator{}
+	gen = gen.encodeSegments(&segs, eccLOW, 7, 7, -1, false)
+	roles := gen.ModuleRoles()
+	for i, data := range ModuleRoles_TestData {
+		if actual := roles[data.y][data.x]; actual != data.expected {
+			test.Errorf(
+				"module_role.Test_ModuleRoles[%d]:\n\tactual -> %+v\n is not equal to\n\texpected -> %+v",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+var ModuleRolesCount_TestData = []struct {
+	version  int
+	ecl      eccType
+	expected [10]int
+}{
+	{version: 1, ecl: eccLOW, expected: [10]int{147, 45, 10, 0, 30, 0, 1, 152, 56, 0}},
+	{version: 2, ecl: eccHIGH, expected: [10]int{147, 45, 18, 25, 30, 0, 1, 128, 224, 7}},
+}
+
+func Test_ModuleRolesCount(test *testing.T) {
+	for i, data := range ModuleRolesCount_TestData {
+		segs, _ := makeSegments("1")
+		gen := Generator{}
+		gen = gen.encodeSegments(&segs, data.ecl, data.version, data.version, -1, false)
+		var actual [10]int
+		bits := map[int]int{}
+		for _, row := range gen.ModuleRoles() {
+			for _, info := range row {
+				actual[info.Role]++
+				if info.Codeword >= 0 {
+					bits[info.Codeword] |= 1 << uint(info.Bit)
+				}
+			}
+		}
+		if actual != data.expected {
+			test.Errorf(
+				"module_role.Test_ModuleRolesCount[%d]:\n\tactual -> %v\n is not equal to\n\texpected -> %v",
+				i, actual, data.expected,
+			)
+		}
+		// Every codeword has exactly 8 distinct bits.
+		for codeword, mask := range bits {
+			if mask != 0xff {
+				test.Errorf("module_role.Test_ModuleRolesCount[%d]:\n\tcodeword %d has bits %08b", i, codeword, mask)
+			}
+		}
+	}
+}
+
+func Test_ModuleRoleString(test *testing.T) {
+	if actual := RoleVersionInfo.String(); actual != "version info" {
+		test.Errorf("module_role.Test_ModuleRoleString:\n\tactual -> %s\n is not equal to\n\texpected -> version info", actual)
+	}
+	if actual := ModuleRole(42).String(); actual != "ModuleRole(42)" {
+		test.Errorf("module_role.Test_ModuleRoleString:\n\tactual -> %s\n is not equal to\n\texpected -> ModuleRole(42)", actual)
+	}
+}
@@ -0,0 +1,148 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image/png"
+	"io"
+	"math"
+	"os"
+)
+
+const (
+	// Smallest module size in millimetres that is considered to be reliably
+	// printable and scannable by common devices.
+	minPrintModuleSize = 0.25
+
+	// Rule of thumb: a QR Code can be scanned from a distance up to
+	// ten times the width of the symbol.
+	scanDistanceRatio = 10.0
+
+	mmPerInch = 25.4
+)
+
+// Describes the physical size of a printed QR Code.
+type PrintOptions struct {
+	// Width and height of one module in millimetres.
+	ModuleSize float64
+
+	// Target printer resolution in dots per inch, used to round the module size
+	// to whole pixels.
+	DPI uint
+
+	// Width of the quiet zone in modules.
+	Margin uint
+
+	// Expected maximum scanning distance in millimetres.
+	// If positive, the module size is validated against it (see MinModuleSize).
+	ScanDistance float64
+}
+
+// Returns the smallest module size in millimetres for generated QR Code
+// to be scanned from the given distance in millimetres.
+func (gen *Generator) MinModuleSize(scanDistance float64) float64 {
+	return math.Max(minPrintModuleSize, scanDistance/scanDistanceRatio/float64(gen.getSize()))
+}
+
+// Draws generated QR Code as a PNG image of the physical size described
+// by opts and saves it to a file.
+func (gen *Generator) DrawPrintImage(path string, opts PrintOptions) {
+	var buf bytes.Buffer
+	if err := gen.encodePrintImage("DrawPrintImage", &buf, opts); err != nil {
+		panic(err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+	if _, err = buf.WriteTo(file); err != nil {
+		panic(err)
+	}
+}
+
+// Writes generated QR Code to w as a PNG image of the physical size described
+// by opts. The pixel size of a module is the module size at the given DPI rounded
+// to whole pixels, and the pHYs chunk declares the resolution at which these
+// pixels take exactly the module size, so that layout tools print the code at the
+// intended size. The declared resolution therefore differs slightly from DPI,
+// e.g. a module of 0.3mm at 300 DPI takes 4 pixels, declared at about 338.7 DPI.
+func (gen *Generator) EncodePrintImage(w io.Writer, opts PrintOptions) error {
+	return gen.encodePrintImage("EncodePrintImage", w, opts)
+}
+
+// Returns the number of pixels per module for the given physical module size
+// in millimetres and resolution in dots per inch.
+func (gen *Generator) printScale(method string, opts PrintOptions) (uint, error) {
+	if opts.DPI == 0 {
+		return 0, generatorErr(method, "DPI must be positive")
+	}
+	if opts.ModuleSize < minPrintModuleSize {
+		return 0, generatorErr(method, fmt.Sprintf("module size %gmm is less than minimum %gmm", opts.ModuleSize, minPrintModuleSize))
+	}
+	if opts.ScanDistance > 0 {
+		if minSize := gen.MinModuleSize(opts.ScanDistance); opts.ModuleSize < minSize {
+			return 0, generatorErr(method, fmt.Sprintf(
+				"module size %gmm is less than %.2fmm required for scanning distance %gmm",
+				opts.ModuleSize, minSize, opts.ScanDistance,
+			))
+		}
+	}
+	scale := uint(math.Floor(opts.ModuleSize/mmPerInch*float64(opts.DPI) + 0.5))
+	if scale == 0 {
+		return 0, generatorErr(method, fmt.Sprintf("module size %gmm is less than one dot at %d DPI", opts.ModuleSize, opts.DPI))
+	}
+	return scale, nil
+}
+
+// Helper method for EncodePrintImage and DrawPrintImage.
+func (gen *Generator) encodePrintImage(method string, w io.Writer, opts PrintOptions) error {
+	scale, err := gen.printScale(method, opts)
+	if err != nil {
+		return err
+	}
+	img, err := gen.toImage(method, opts.Margin, (uint(gen.getSize())+opts.Margin*2)*scale)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, img); err != nil {
+		return err
+	}
+	// Pixels per metre, so that scale pixels take exactly the module size.
+	resolution := uint32(math.Floor(float64(scale)*1000/opts.ModuleSize + 0.5))
+	data, err := insertPngPhys(buf.Bytes(), resolution)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Returns a copy of the given PNG stream with a pHYs chunk declaring
+// the given resolution in pixels per metre inserted right after the IHDR chunk.
+func insertPngPhys(data []byte, pixelsPerMetre uint32) ([]byte, error) {
+	// 8 bytes of signature, then IHDR: length, type, 13 bytes of data and CRC.
+	const ihdrEnd = 8 + 4 + 4 + 13 + 4
+	if len(data) < ihdrEnd || string(data[12:16]) != "IHDR" {
+		return nil, generatorErr("insertPngPhys", "invalid PNG stream")
+	}
+	chunk := make([]byte, 4+4+9+4)
+	binary.BigEndian.PutUint32(chunk[0:], 9)
+	copy(chunk[4:], "pHYs")
+	binary.BigEndian.PutUint32(chunk[8:], pixelsPerMetre)
+	binary.BigEndian.PutUint32(chunk[12:], pixelsPerMetre)
+	// Unit specifier: 1 means the metre.
+	chunk[16] = 1
+	binary.BigEndian.PutUint32(chunk[17:], crc32.ChecksumIEEE(chunk[4:17]))
+	result := make([]byte, 0, len(data)+len(chunk))
+	result = append(result, data[:ihdrEnd]...)
+	result = append(result, chunk...)
+	return append(result, data[ihdrEnd:]...), nil
+}
@@ -0,0 +1,139 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image/png"
+	"math"
+	"testing"
+)
+
+func Test_EncodePrintImage(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	var buf bytes.Buffer
+	err := gen.EncodePrintImage(&buf, PrintOptions{ModuleSize: 0.5, DPI: 300, Margin: 4})
+	if err != nil {
+		test.Fatalf("print_image.Test_EncodePrintImage:\n\tunexpected error -> %s", err)
+	}
+	data := buf.Bytes()
+	if string(data[37:41]) != "pHYs" {
+		test.Fatalf("print_image.Test_EncodePrintImage:\n\tpHYs chunk is not found after IHDR")
+	}
+	if x, y := binary.BigEndian.Uint32(data[41:]), binary.BigEndian.Uint32(data[45:]); x != 12000 || y != 12000 || data[49] != 1 {
+		test.Errorf("print_image.Test_EncodePrintImage:\n\tinvalid pHYs data -> %d, %d, %d", x, y, data[49])
+	}
+	if crc := binary.BigEndian.Uint32(data[50:]); crc != crc32.ChecksumIEEE(data[37:50]) {
+		test.Errorf("print_image.Test_EncodePrintImage:\n\tinvalid pHYs CRC -> %x", crc)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		test.Fatalf("print_image.Test_EncodePrintImage:\n\tdecoding failed -> %s", err)
+	}
+	// 21 modules and 2 * 4 modules of margin, 6 pixels per module.
+	if img.Bounds().Dx() != 174 || img.Bounds().Dy() != 174 {
+		test.Errorf("print_image.Test_EncodePrintImage:\n\tactual bounds -> %v\n is not equal to\n\texpected size -> 174", img.Bounds())
+	}
+}
+
+var EncodePrintImageModuleSize_TestData = []struct {
+	opts  PrintOptions
+	scale int
+}{
+	{opts: PrintOptions{ModuleSize: 0.5, DPI: 300}, scale: 6},
+	{opts: PrintOptions{ModuleSize: 0.3, DPI: 300}, scale: 4},
+	{opts: PrintOptions{ModuleSize: 0.254, DPI: 600, Margin: 2}, scale: 6},
+	{opts: PrintOptions{ModuleSize: 1.7, DPI: 203, Margin: 4}, scale: 14},
+}
+
+func Test_EncodePrintImageModuleSize(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	for i, data := range EncodePrintImageModuleSize_TestData {
+		var buf bytes.Buffer
+		if err := gen.EncodePrintImage(&buf, data.opts); err != nil {
+			test.Errorf("print_image.Test_EncodePrintImageModuleSize[%d]:\n\tunexpected error -> %s", i, err)
+			continue
+		}
+		img, _ := png.Decode(bytes.NewReader(buf.Bytes()))
+		if expected := (21 + 2*int(data.opts.Margin)) * data.scale; img.Bounds().Dx() != expected {
+			test.Errorf(
+				"print_image.Test_EncodePrintImageModuleSize[%d]:\n\tactual width -> %d\n is not equal to\n\texpected -> %d",
+				i, img.Bounds().Dx(), expected,
+			)
+		}
+		// The printed module size is derived from the declared resolution.
+		pixelsPerMetre := binary.BigEndian.Uint32(buf.Bytes()[41:])
+		if actual := float64(data.scale) * 1000 / float64(pixelsPerMetre); math.Abs(actual-data.opts.ModuleSize) > 0.001 {
+			test.Errorf(
+				"print_image.Test_EncodePrintImageModuleSize[%d]:\n\tactual module size -> %gmm\n is not equal to\n\texpected -> %gmm",
+				i, actual, data.opts.ModuleSize,
+			)
+		}
+	}
+}
+
+var EncodePrintImageErr_TestData = []struct {
+	opts     PrintOptions
+	expected error
+}{
+	{
+		opts:     PrintOptions{ModuleSize: 0.5},
+		expected: generatorErr("EncodePrintImage", "DPI must be positive"),
+	},
+	{
+		opts:     PrintOptions{ModuleSize: 0.2, DPI: 600},
+		expected: generatorErr("EncodePrintImage", "module size 0.2mm is less than minimum 0.25mm"),
+	},
+	{
+		opts:     PrintOptions{ModuleSize: 0.5, DPI: 600, ScanDistance: 300},
+		expected: generatorErr("EncodePrintImage", "module size 0.5mm is less than 1.43mm required for scanning distance 300mm"),
+	},
+	{
+		opts:     PrintOptions{ModuleSize: 0.3, DPI: 40},
+		expected: generatorErr("EncodePrintImage", "module size 0.3mm is less than one dot at 40 DPI"),
+	},
+}
+
+func Test_EncodePrintImageErr(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	for i, data := range EncodePrintImageErr_TestData {
+		var buf bytes.Buffer
+		actual := gen.EncodePrintImage(&buf, data.opts)
+		if actual == nil || actual.Error() != data.expected.Error() {
+			test.Errorf(
+				"print_image.Test_EncodePrintImageErr[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+var MinModuleSize_TestData = []struct {
+	scanDistance float64
+	expected     float64
+}{
+	{scanDistance: 0, expected: 0.25},
+	{scanDistance: 21, expected: 0.25},
+	{scanDistance: 210, expected: 1},
+	{scanDistance: 1050, expected: 5},
+}
+
+func Test_MinModuleSize(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	for i, data := range MinModuleSize_TestData {
+		if actual := gen.MinModuleSize(data.scanDistance); math.Abs(actual-data.expected) > 1e-9 {
+			test.Errorf(
+				"print_image.Test_MinModuleSize[%d]:\n\tactual size -> %g\n is not equal to\n\texpected size -> %g",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
@@ -9,7 +9,7 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
-	"image/png"
+	"io"
 	"math"
 	"os"
 
@@ -41,6 +41,14 @@ type Generator struct {
 
 	// Indicates function modules that are not subjected to masking
 	isFunction [][]bool
+
+	// The data segments this QR Code symbol was encoded from,
+	// kept to re-encode the data with other parameters.
+	segments []qrSegment
+
+	// Optional gradient fill of black modules used by ToSvg and raster renderers
+	// (see WithGradient), with sorted stops.
+	fill *Gradient
 }
 
 // Returns a QR Code symbol representing the specified Unicode text string at the specified error correction level.
@@ -57,6 +65,100 @@ func (gen *Generator) EncodeText(text string) Generator {
 	return gen.encodeSegments(&segments, eccLOW, 1, 40, -1, true)
 }
 
+// Returns a QR Code symbol representing the specified Unicode text string at the given error correction
+// level or higher, if it can be done without increasing the version. Unlike EncodeText, returns an error
+// instead of panicking if the text does not fit into the largest symbol at the given level.
+func (gen *Generator) EncodeTextAt(text string, ecl EccLevel) (Generator, error) {
+	return gen.encodeTextAt("EncodeTextAt", text, eccType(ecl), true)
+}
+
+// Returns a QR Code symbol representing the specified Unicode text string at exactly the given error
+// correction level, for payloads whose specification mandates one. Returns an error if the text does not fit.
+func (gen *Generator) EncodeTextExact(text string, ecl EccLevel) (Generator, error) {
+	return gen.encodeTextAt("EncodeTextExact", text, eccType(ecl), false)
+}
+
+// Encodes text at the given error correction level, reporting errors on behalf of method.
+func (gen *Generator) encodeTextAt(method, text string, ecl eccType, boostEcl bool) (Generator, error) {
+	if ecl > eccHIGH {
+		return Generator{}, generatorErr(method, "invalid error correction level")
+	}
+	segments, err := makeSegments(text)
+	if err != nil {
+		return Generator{}, err
+	}
+	if gen.minFittingVersion(&segments, ecl) == -1 {
+		return Generator{}, generatorErr(method, "data too long")
+	}
+	return gen.encodeSegments(&segments, ecl, minVersion, maxVersion, -1, boostEcl), nil
+}
+
+// Returns the version of the symbol EncodeTextAt would produce for the given text
+// and error correction level, without encoding it. Use it to compare the sizes
+// of alternative payloads.
+func (gen *Generator) TextVersion(text string, ecl EccLevel) (int, error) {
+	if ecl > EccHigh {
+		return 0, generatorErr("TextVersion", "invalid error correction level")
+	}
+	segments, err := makeSegments(text)
+	if err != nil {
+		return 0, err
+	}
+	version := gen.minFittingVersion(&segments, eccType(ecl))
+	if version == -1 {
+		return 0, generatorErr("TextVersion", "data too long")
+	}
+	return version, nil
+}
+
+// Returns a QR Code symbol representing the specified Unicode text string at the given error correction
+// level or higher, like EncodeTextAt, but splits the text into byte, alphanumeric and numeric segments
+// where switching modes shortens the bit stream. Text with uppercase runs, e.g. "HTTPS://EXAMPLE.COM/a1b2",
+// often fits into a smaller version this way.
+func (gen *Generator) EncodeMixedText(text string, ecl EccLevel) (Generator, error) {
+	segments, version, err := gen.mixedSegments("EncodeMixedText", text, eccType(ecl))
+	if err != nil {
+		return Generator{}, err
+	}
+	return gen.encodeSegments(&segments, eccType(ecl), version, version, -1, true), nil
+}
+
+// Returns the version of the symbol EncodeMixedText would produce for the given text
+// and error correction level, without encoding it.
+func (gen *Generator) MixedTextVersion(text string, ecl EccLevel) (int, error) {
+	_, version, err := gen.mixedSegments("MixedTextVersion", text, eccType(ecl))
+	return version, err
+}
+
+// Returns the smallest version which fits the optimal mixed segmentation
+// of the text at the given error correction level, and its segments.
+func (gen *Generator) mixedSegments(method, text string, ecl eccType) ([]qrSegment, int, error) {
+	if ecl > eccHIGH {
+		return nil, 0, generatorErr(method, "invalid error correction level")
+	}
+	for version := minVersion; version <= maxVersion; version++ {
+		segments, err := makeMixedSegments(text, version)
+		if err != nil {
+			return nil, 0, err
+		}
+		if bits, _ := getTotalBits(&segments, version); bits != -1 && bits <= gen.getNumDataCodewords(version, ecl)*8 {
+			return segments, version, nil
+		}
+	}
+	return nil, 0, generatorErr(method, "data too long")
+}
+
+// Returns the smallest version which fits the given segments at the
+// given error correction level, or -1 if even the largest one does not.
+func (gen *Generator) minFittingVersion(segs *[]qrSegment, ecl eccType) int {
+	for version := minVersion; version <= maxVersion; version++ {
+		if bits, _ := getTotalBits(segs, version); bits != -1 && bits <= gen.getNumDataCodewords(version, ecl)*8 {
+			return version
+		}
+	}
+	return -1
+}
+
 // Returns a QR Code symbol representing the given binary data string at the given error correction level.
 //
 // This function always encodes using the binary segment mode, not any text mode. The maximum number of
@@ -86,36 +188,41 @@ func (gen *Generator) Draw(margin int) {
 }
 
 // Draws generated QR Code and save it to a file.
+//
+// Black modules are filled with the gradient if one is set (see WithGradient).
+// The image format is inferred from the file extension (see ImageFormat).
+// PNG is written if the extension is missing or unknown, as before other formats
+// were supported; use EncodeImage to choose the format explicitly.
 func (gen *Generator) DrawImage(path string, margin, pictureSize uint) {
-	if margin < 0 {
-		panic(generatorErr("DrawImage", "margin is negative"))
-	}
-	size := gen.getSize() + int(margin)*2
-	if int(pictureSize) < size {
-		panic(generatorErr("DrawImage", fmt.Sprintf("size of code is less than minimum size > %dx%d", size, size)))
+	format, err := imageFormatFromPath(path)
+	if err != nil {
+		format = FormatPNG
 	}
-	img := image.NewRGBA(image.Rect(0, 0, size, size))
-	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{R: 255, G: 255, B: 255, A: 255}}, image.ZP, draw.Src)
-	for y := 0; y < size; y++ {
-		for x := 0; x < size; x++ {
-			if gen.getModule(x, y) {
-				img.Set(x+int(margin), y+int(margin), color.RGBA{R: 0, G: 0, B: 0, A: 255})
-			}
-		}
+	img, err := gen.toImage("DrawImage", margin, pictureSize)
+	if err != nil {
+		panic(err)
 	}
 	file, err := os.Create(path)
-	defer file.Close()
 	if err != nil {
 		panic(err)
 	}
-	if int(pictureSize) > size {
-		png.Encode(file, resize.Resize(pictureSize, pictureSize, img, resize.NearestNeighbor))
-	} else {
-		png.Encode(file, img)
+	defer file.Close()
+	if err = encodeImage(file, img, format); err != nil {
+		panic(err)
+	}
+}
+
+// Writes generated QR Code to w as an image of the given format.
+func (gen *Generator) EncodeImage(w io.Writer, format ImageFormat, margin, pictureSize uint) error {
+	img, err := gen.toImage("EncodeImage", margin, pictureSize)
+	if err != nil {
+		return err
 	}
+	return encodeImage(w, img, format)
 }
 
-// Returns svg string of generated QR Code.
+// Returns svg string of generated QR Code, black modules are filled with
+// the gradient if one is set (see WithGradient).
 func (gen *Generator) ToSvg(border int) (ret string, err error) {
 	if border < 0 {
 		return "", generatorErr("ToSvg", "negative border was given")
@@ -127,8 +234,11 @@ func (gen *Generator) ToSvg(border int) (ret string, err error) {
 	"<!DOCTYPE svg PUBLIC \"-//W3C//DTD SVG 1.1//EN\" \"http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd\">\n" +
 	"<svg xmlns=\"http://www.w3.org/2000/svg\" version=\"1.1\" viewBox=\"0 0 " +
 	fmt.Sprintf("%d %d\" stroke=\"none\">\n", gen.size + border * 2, gen.size + border * 2) +
-	"\t<rect width=\"100%\" height=\"100%\" fill=\"#FFFFFF\"/>\n" +
-	"\t<path d=\""
+	"\t<rect width=\"100%\" height=\"100%\" fill=\"#FFFFFF\"/>\n"
+	if gen.fill != nil {
+		ret += "\t<defs>\n" + gen.fill.svgDef("fill", gen.size, border) + "\t</defs>\n"
+	}
+	ret += "\t<path d=\""
 	head := true
 	for y := -border; y < gen.size + border; y++ {
 		for x := -border; x < gen.size + border; x++ {
@@ -142,10 +252,19 @@ func (gen *Generator) ToSvg(border int) (ret string, err error) {
 			}
 		}
 	}
+	if gen.fill != nil {
+		ret += "\" fill=\"url(#fill)\"/>\n</svg>"
+		return
+	}
 	ret += "\" fill=\"#000000\"/>\n</svg>"
 	return
 }
 
+// Returns the error correction level of generated QR Code.
+func (gen *Generator) GetErrorCorrectionLevel() EccLevel {
+	return EccLevel(gen.errorCorrectionLevel)
+}
+
 // Returns boolean matrix of modules of generated QR Code.
 func (gen *Generator) GetModules() [][]bool {
 	size := gen.getSize()
@@ -160,6 +279,65 @@ func (gen *Generator) GetModules() [][]bool {
 	return matrix
 }
 
+// Returns generated QR Code drawn in black and white with the given margin
+// and scaled to pictureSize x pictureSize pixels.
+//
+// Helper method for raster renderers.
+func (gen *Generator) toImage(method string, margin, pictureSize uint) (image.Image, error) {
+	size := gen.getSize() + int(margin)*2
+	if int(pictureSize) < size {
+		return nil, generatorErr(method, fmt.Sprintf("size of code is less than minimum size > %dx%d", size, size))
+	}
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{R: 255, G: 255, B: 255, A: 255}}, image.ZP, draw.Src)
+	for y := 0; y < gen.getSize(); y++ {
+		for x := 0; x < gen.getSize(); x++ {
+			if gen.getModule(x, y) {
+				img.Set(x+int(margin), y+int(margin), color.RGBA{R: 0, G: 0, B: 0, A: 255})
+			}
+		}
+	}
+	var result image.Image = img
+	if int(pictureSize) > size {
+		result = resize.Resize(pictureSize, pictureSize, img, resize.NearestNeighbor)
+	}
+	if gen.fill != nil {
+		return gen.fillImage(result, margin), nil
+	}
+	return result, nil
+}
+
+// Returns a copy of this QR Code whose black modules are filled with the given
+// gradient by ToSvg, DrawImage, EncodeImage and other renderers built on them.
+// Monochrome image formats (GIF, BMP, PBM) still draw black modules black.
+// Returns an error if any gradient stop does not contrast enough with the white background.
+func (gen *Generator) WithGradient(fill Gradient) (Generator, error) {
+	if err := fill.validate("WithGradient", color.White); err != nil {
+		return Generator{}, err
+	}
+	result := *gen
+	result.fill = fill.sorted()
+	return result, nil
+}
+
+// Returns a copy of the given black and white image of this QR Code with black
+// pixels colored by the gradient fill at their centers.
+func (gen *Generator) fillImage(img image.Image, margin uint) image.Image {
+	bounds := img.Bounds()
+	filled := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	scale := float64(gen.size+int(margin)*2) / float64(bounds.Dx())
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := toRGBA(img.At(bounds.Min.X+x, bounds.Min.Y+y))
+			if c.R == 0 {
+				c = gen.fill.colorAt((float64(x)+0.5)*scale-float64(margin), (float64(y)+0.5)*scale-float64(margin), gen.size)
+			}
+			filled.SetRGBA(x, y, c)
+		}
+	}
+	return filled
+}
+
 // Creates a new QR Code symbol with the given version number, error correction level, binary data array,
 // and mask number. This is a cumbersome low-level constructor that should not be invoked directly by the user.
 // To go one level up, see the encodeSegments() function.
@@ -272,7 +450,9 @@ func (gen *Generator) encodeSegments(segs *[]qrSegment, ecl eccType, minVersion,
 	if len(bitBuf)%8 != 0 {
 		panic(generatorErr("encodeSegments", "assertion error"))
 	}
-	return newQrCode(version, ecl, bitBuf.getBytes(), mask)
+	result := newQrCode(version, ecl, bitBuf.getBytes(), mask)
+	result.segments = *segs
+	return result
 }
 
 // Instance method.
@@ -498,28 +678,13 @@ func (gen *Generator) drawCodewords(data *[]uint8) {
 	if len(*data) != gen.getNumRawDataModules(gen.version)/8 {
 		panic(generatorErr("drawCodewords", "invalid argument"))
 	}
-	i := uint(0)
-	for right := gen.size - 1; right >= 1; right -= 2 {
-		if right == 6 {
-			right = 5
-		}
-		for vert := 0; vert < gen.size; vert++ {
-			for j := 0; j < 2; j++ {
-				x := int(right - j)
-				y := vert
-				if ((right + 1) & 2) == 0 {
-					y = gen.size - 1 - vert
-				}
-				if !gen.isFunction[y][x] && i < uint(len(*data)*8) {
-					gen.modules[y][x] = gen.getBit(int((*data)[i>>3]), uint(7-(int(i & 7))))
-					i++
-				}
-			}
-		}
-	}
-	if i != uint(len(*data)*8) {
+	order := gen.dataModuleOrder()
+	if len(order) < len(*data)*8 {
 		panic(generatorErr("drawCodewords", "assertion error"))
 	}
+	for i := 0; i < len(*data)*8; i++ {
+		gen.modules[order[i].Y][order[i].X] = gen.getBit(int((*data)[i>>3]), uint(7-(i&7)))
+	}
 }
 
 // XORs the data modules in this QR Code with the given mask pattern. Due to XOR's mathematical
@@ -748,3 +748,104 @@ func Test_drawAlignmentPattern(test *testing.T) {
 		}
 	}
 }
+
+var EncodeTextAt_TestData = []struct {
+	text            string
+	ecl             EccLevel
+	expectedVersion int
+	expectedEcl     eccType
+}{
+	{text: "HELLO WORLD", ecl: EccLow, expectedVersion: 1, expectedEcl: eccQUARTILE},
+	{text: "HELLO WORLD", ecl: EccHigh, expectedVersion: 2, expectedEcl: eccHIGH},
+	{text: "https://github.com/YuriyLisovskiy/qrcode", ecl: EccMedium, expectedVersion: 3, expectedEcl: eccMEDIUM},
+}
+
+func Test_EncodeTextAt(test *testing.T) {
+	for i, data := range EncodeTextAt_TestData {
+		gen := Generator{}
+		actual, err := gen.EncodeTextAt(data.text, data.ecl)
+		if err != nil || actual.version != data.expectedVersion || actual.errorCorrectionLevel != data.expectedEcl {
+			test.Errorf(
+				"qr_generator.Test_EncodeTextAt[%d]:\n\tactual -> version %d, ecl %d (err: %v)\n is not equal to\n\texpected -> version %d, ecl %d",
+				i, actual.version, actual.errorCorrectionLevel, err, data.expectedVersion, data.expectedEcl,
+			)
+		}
+	}
+	gen := Generator{}
+	if _, err := gen.EncodeTextAt(string(make([]byte, 2400)), EccHigh); err == nil {
+		test.Errorf("qr_generator.Test_EncodeTextAt:\n\ttoo long text is accepted")
+	}
+	if _, err := gen.EncodeTextAt("HELLO", EccLevel(4)); err == nil {
+		test.Errorf("qr_generator.Test_EncodeTextAt:\n\tinvalid error correction level is accepted")
+	}
+}
+
+func Test_EncodeTextExact(test *testing.T) {
+	gen := Generator{}
+	actual, err := gen.EncodeTextExact("HELLO WORLD", EccLow)
+	if err != nil || actual.version != 1 || actual.errorCorrectionLevel != eccLOW {
+		test.Errorf(
+			"qr_generator.Test_EncodeTextExact:\n\tactual -> version %d, ecl %d (err: %v)\n is not equal to\n\texpected -> version 1, ecl %d",
+			actual.version, actual.errorCorrectionLevel, err, eccLOW,
+		)
+	}
+	if _, err := gen.EncodeTextExact(string(make([]byte, 2400)), EccHigh); err == nil {
+		test.Errorf("qr_generator.Test_EncodeTextExact:\n\ttoo long text is accepted")
+	}
+}
+
+func Test_EncodeMixedText(test *testing.T) {
+	const text = "HTTPS://EXAMPLE.COM/x"
+	gen := Generator{}
+	actual, err := gen.EncodeMixedText(text, EccLow)
+	if err != nil || actual.version != 1 {
+		test.Errorf("qr_generator.Test_EncodeMixedText:\n\tactual version -> %d (err: %v)\n is not equal to\n\texpected -> 1", actual.version, err)
+	}
+	// A single byte mode segment does not fit into version 1.
+	if version, _ := gen.TextVersion(text, EccLow); version != 2 {
+		test.Errorf("qr_generator.Test_EncodeMixedText:\n\tactual byte mode version -> %d\n is not equal to\n\texpected -> 2", version)
+	}
+	if version, err := gen.MixedTextVersion(text, EccLow); err != nil || version != 1 {
+		test.Errorf("qr_generator.Test_EncodeMixedText:\n\tactual mixed version -> %d (err: %v)\n is not equal to\n\texpected -> 1", version, err)
+	}
+	if decoded, err := decodeModules(actual.modules); err != nil || decoded != text {
+		test.Errorf("qr_generator.Test_EncodeMixedText:\n\tactual decoded -> %q (err: %v)\n is not equal to\n\texpected -> %q", decoded, err, text)
+	}
+	// Text which is not valid UTF-8 keeps its bytes, like with EncodeText.
+	const binary = "\xffHTTPS://EXAMPLE.COM/\xfe\x80"
+	if actual, err := gen.EncodeMixedText(binary, EccLow); err != nil {
+		test.Errorf("qr_generator.Test_EncodeMixedText:\n\tunexpected error -> %s", err)
+	} else if decoded, err := decodeModules(actual.modules); err != nil || decoded != binary {
+		test.Errorf("qr_generator.Test_EncodeMixedText:\n\tactual decoded -> %q (err: %v)\n is not equal to\n\texpected -> %q", decoded, err, binary)
+	}
+	if _, err := gen.EncodeMixedText(string(make([]byte, 2400)), EccHigh); err == nil {
+		test.Errorf("qr_generator.Test_EncodeMixedText:\n\ttoo long text is accepted")
+	}
+}
+
+var TextVersion_TestData = []struct {
+	text     string
+	ecl      EccLevel
+	expected int
+}{
+	{text: "HELLO WORLD", ecl: EccLow, expected: 1},
+	{text: "HELLO WORLD", ecl: EccHigh, expected: 2},
+	{text: "https://github.com/YuriyLisovskiy/qrcode", ecl: EccMedium, expected: 3},
+	{text: string(make([]byte, 1273)), ecl: EccHigh, expected: 40},
+}
+
+func Test_TextVersion(test *testing.T) {
+	gen := Generator{}
+	for i, data := range TextVersion_TestData {
+		actual, err := gen.TextVersion(data.text, data.ecl)
+		if err != nil || actual != data.expected {
+			test.Errorf(
+				"qr_generator.Test_TextVersion[%d]:\n\tactual -> %d (err: %v)\n is not equal to\n\texpected -> %d",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+	if _, err := gen.TextVersion(string(make([]byte, 1274)), EccHigh); err == nil {
+		test.Errorf("qr_generator.Test_TextVersion:\n\ttoo long text is accepted")
+	}
+}
@@ -133,6 +133,89 @@ func makeSegments(text string) (result []qrSegment, err error) {
 	return
 }
 
+// Returns a list of segments representing the given text string with the shortest bit stream
+// at the given version, switching between byte, alphanumeric and numeric modes where it pays off.
+// Costs are counted in sixths of a bit, so that numeric (10/3 bits) and alphanumeric (11/2 bits)
+// characters are whole numbers; a new segment costs its mode indicator and character count field.
+// The text is split by bytes, which keeps text that is not valid UTF-8 intact: characters
+// of the alphanumeric and numeric modes are all ASCII, so every other byte goes to byte mode.
+func makeMixedSegments(text string, version int) ([]qrSegment, error) {
+	modes := [3]modeType{isBYTE, isALPHANUMERIC, isNUMERIC}
+	var headCosts [3]int
+	for i, mode := range modes {
+		ccbits, err := mode.numCharCountBits(version)
+		if err != nil {
+			return nil, err
+		}
+		headCosts[i] = (4 + ccbits) * 6
+	}
+	chars := []byte(text)
+	// charModes[i][j] is the mode of the i-th character on the cheapest
+	// way to end with a segment of the j-th mode after it, or -1.
+	charModes := make([][3]int, len(chars))
+	prevCosts := headCosts
+	for i, char := range chars {
+		var curCosts [3]int
+		charModes[i] = [3]int{0, -1, -1}
+		curCosts[0] = prevCosts[0] + 8*6
+		if strings.IndexByte(alphanumericCharset, char) != -1 {
+			curCosts[1] = prevCosts[1] + 33
+			charModes[i][1] = 1
+		}
+		if '0' <= char && char <= '9' {
+			curCosts[2] = prevCosts[2] + 20
+			charModes[i][2] = 2
+		}
+		// Start a new segment after the character to switch modes.
+		for to := range modes {
+			for from := range modes {
+				cost := (curCosts[from]+5)/6*6 + headCosts[to]
+				if charModes[i][from] != -1 && (charModes[i][to] == -1 || cost < curCosts[to]) {
+					curCosts[to] = cost
+					charModes[i][to] = from
+				}
+			}
+		}
+		prevCosts = curCosts
+	}
+	mode := 0
+	for i := range prevCosts {
+		if prevCosts[i] < prevCosts[mode] {
+			mode = i
+		}
+	}
+	charMode := make([]int, len(chars))
+	for i := len(chars) - 1; i >= 0; i-- {
+		mode = charModes[i][mode]
+		charMode[i] = mode
+	}
+	var result []qrSegment
+	for start := 0; start < len(chars); {
+		end := start + 1
+		for end < len(chars) && charMode[end] == charMode[start] {
+			end++
+		}
+		run := string(chars[start:end])
+		var seg qrSegment
+		var err error
+		switch charMode[start] {
+		case 0:
+			data := []uint8(run)
+			seg, err = makeBytes(&data)
+		case 1:
+			seg, err = makeAlphanumeric(run)
+		default:
+			seg, err = makeNumeric(run)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, seg)
+		start = end
+	}
+	return result, nil
+}
+
 // Returns a segment representing an Extended Channel Interpretation
 // (ECI) designator with the given assignment value.
 func makeEci(assignVal int64) (qrSegment, error) {
@@ -1124,3 +1124,41 @@ func Test_getData(test *testing.T) {
 		}
 	}
 }
+
+var makeMixedSegments_TestData = []struct {
+	text          string
+	version       int
+	expectedModes []int
+	expectedChars []int
+}{
+	{text: "", version: 1},
+	{text: "HTTPS://BIT.LY/AbC", version: 1, expectedModes: []int{0x2, 0x4}, expectedChars: []int{16, 2}},
+	{text: "abc123456789012def", version: 1, expectedModes: []int{0x4, 0x1, 0x4}, expectedChars: []int{3, 12, 3}},
+	// Short runs of digits do not pay off the segment header.
+	{text: "abc12def", version: 1, expectedModes: []int{0x4}, expectedChars: []int{8}},
+	{text: "Ж1", version: 1, expectedModes: []int{0x4}, expectedChars: []int{3}},
+	// Bytes which are not valid UTF-8 are kept as they are.
+	{text: "\xff\xfe012345678901\x80", version: 1, expectedModes: []int{0x4, 0x1, 0x4}, expectedChars: []int{2, 12, 1}},
+	{text: "0123456789", version: 40, expectedModes: []int{0x1}, expectedChars: []int{10}},
+}
+
+func Test_makeMixedSegments(test *testing.T) {
+	for i, data := range makeMixedSegments_TestData {
+		actual, err := makeMixedSegments(data.text, data.version)
+		if err != nil || len(actual) != len(data.expectedModes) {
+			test.Errorf(
+				"qr_segment.Test_makeMixedSegments[%d]:\n\tactual segments -> %d (err: %v)\n is not equal to\n\texpected segments -> %d",
+				i, len(actual), err, len(data.expectedModes),
+			)
+			continue
+		}
+		for j, seg := range actual {
+			if seg.Mode.modeBits != data.expectedModes[j] || seg.NumChars != data.expectedChars[j] {
+				test.Errorf(
+					"qr_segment.Test_makeMixedSegments[%d]:\n\tactual segment %d -> mode %d, %d chars\n is not equal to\n\texpected -> mode %d, %d chars",
+					i, j, seg.Mode.modeBits, seg.NumChars, data.expectedModes[j], data.expectedChars[j],
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,232 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// Tells which of the four adjacent modules of a data module are black.
+type Neighbours struct {
+	Top, Right, Bottom, Left bool
+}
+
+// Represents a pluggable shape used to draw modules and parts of finder patterns.
+// A shape is drawn inside a square with the given side, measured in modules.
+// Finder pattern parts are always drawn without neighbours.
+type Shape interface {
+	// Returns true if the point (x, y), relative to the top left
+	// corner of the square, lies inside the shape.
+	Contains(x, y, side float64, n Neighbours) bool
+
+	// Returns SVG path data of the shape drawn inside the square
+	// with the top left corner at (x, y).
+	SvgPath(x, y, side float64, n Neighbours) string
+}
+
+// Describes how a styled QR Code is drawn. Nil shapes are drawn as squares.
+//
+// Module shape is applied to data modules only. Function modules (as marked in
+// isFunction) are always drawn as squares, except for finder patterns, whose
+// outer 7x7 ring and inner 3x3 center are styled separately.
+type Style struct {
+	Module       Shape
+	FinderOuter  Shape
+	FinderCenter Shape
+
+	// Optional logo placed in the center of the code.
+	Logo *Logo
+
+	// Optional gradient fill of black modules.
+	Fill *Gradient
+
+	// Color of white modules and the quiet zone, white if nil.
+	Background color.Color
+}
+
+// Returns an error if the style cannot produce a readable code.
+func (s Style) validate(method string) error {
+	if s.Fill != nil {
+		return s.Fill.validate(method, s.background())
+	}
+	return nil
+}
+
+// Returns a copy of the style prepared for rendering, with the gradient stops sorted.
+func (s Style) sorted() Style {
+	if s.Fill != nil {
+		s.Fill = s.Fill.sorted()
+	}
+	return s
+}
+
+// Returns the color of white modules.
+func (s Style) background() color.RGBA {
+	if s.Background == nil {
+		return color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	}
+	return toRGBA(s.Background)
+}
+
+// Returns the color of black modules at the point (x, y) measured
+// in modules from the top left corner of a symbol with the given size.
+func (s Style) foregroundAt(x, y float64, size int) color.RGBA {
+	if s.Fill == nil {
+		return color.RGBA{A: 255}
+	}
+	return s.Fill.colorAt(x, y, size)
+}
+
+// Returns SVG paint of black modules.
+func (s Style) svgForeground() string {
+	if s.Fill == nil {
+		return "#000000"
+	}
+	return "url(#fill)"
+}
+
+// Plain square.
+type SquareShape struct{}
+
+// Circle inscribed into the square.
+type CircleShape struct{}
+
+// Square with rounded corners. Radius is a fraction of the side in the range [0, 0.5].
+type RoundedShape struct {
+	Radius float64
+}
+
+// Rounded square which connects to its black neighbours: a corner is rounded
+// only when both modules adjacent to that corner are white, so that runs of
+// black modules flow into each other.
+type LiquidShape struct{}
+
+func (SquareShape) Contains(x, y, side float64, _ Neighbours) bool {
+	return roundedRect{side: side}.contains(x, y)
+}
+
+func (SquareShape) SvgPath(x, y, side float64, _ Neighbours) string {
+	return roundedRect{side: side}.svgPath(x, y)
+}
+
+func (CircleShape) Contains(x, y, side float64, _ Neighbours) bool {
+	return newRoundedRect(side, 0.5).contains(x, y)
+}
+
+func (CircleShape) SvgPath(x, y, side float64, _ Neighbours) string {
+	return newRoundedRect(side, 0.5).svgPath(x, y)
+}
+
+func (s RoundedShape) Contains(x, y, side float64, _ Neighbours) bool {
+	return newRoundedRect(side, s.Radius).contains(x, y)
+}
+
+func (s RoundedShape) SvgPath(x, y, side float64, _ Neighbours) string {
+	return newRoundedRect(side, s.Radius).svgPath(x, y)
+}
+
+func (LiquidShape) Contains(x, y, side float64, n Neighbours) bool {
+	return newLiquidRect(side, n).contains(x, y)
+}
+
+func (LiquidShape) SvgPath(x, y, side float64, n Neighbours) string {
+	return newLiquidRect(side, n).svgPath(x, y)
+}
+
+// Square with independently rounded corners.
+type roundedRect struct {
+	side float64
+
+	// Corner radii in clockwise order starting from the top left corner.
+	radii [4]float64
+}
+
+// Returns a square with all corners rounded by the given fraction of its side.
+func newRoundedRect(side, radius float64) roundedRect {
+	r := math.Max(0, math.Min(radius, 0.5)) * side
+	return roundedRect{side: side, radii: [4]float64{r, r, r, r}}
+}
+
+// Returns a square with the corners rounded by a half of its side
+// unless a black neighbour touches that corner.
+func newLiquidRect(side float64, n Neighbours) roundedRect {
+	rect := roundedRect{side: side}
+	corners := [4]bool{
+		!n.Top && !n.Left,
+		!n.Top && !n.Right,
+		!n.Bottom && !n.Right,
+		!n.Bottom && !n.Left,
+	}
+	for i, rounded := range corners {
+		if rounded {
+			rect.radii[i] = side / 2
+		}
+	}
+	return rect
+}
+
+func (r roundedRect) contains(x, y float64) bool {
+	if x < 0 || y < 0 || x >= r.side || y >= r.side {
+		return false
+	}
+	// Centers of the corner circles.
+	centers := [4][2]float64{
+		{r.radii[0], r.radii[0]},
+		{r.side - r.radii[1], r.radii[1]},
+		{r.side - r.radii[2], r.side - r.radii[2]},
+		{r.radii[3], r.side - r.radii[3]},
+	}
+	for i, c := range centers {
+		radius := r.radii[i]
+		if radius == 0 {
+			continue
+		}
+		dx, dy := x-c[0], y-c[1]
+		outside := (i == 0 || i == 3) && dx < 0 || (i == 1 || i == 2) && dx > 0
+		outside = outside && ((i == 0 || i == 1) && dy < 0 || (i == 2 || i == 3) && dy > 0)
+		if outside && dx*dx+dy*dy > radius*radius {
+			return false
+		}
+	}
+	return true
+}
+
+func (r roundedRect) svgPath(x, y float64) string {
+	s := r.side
+	path := fmt.Sprintf("M%s,%sH%s", fmtFloat(x+r.radii[0]), fmtFloat(y), fmtFloat(x+s-r.radii[1]))
+	path += svgArc(r.radii[1], x+s, y+r.radii[1])
+	path += "V" + fmtFloat(y+s-r.radii[2])
+	path += svgArc(r.radii[2], x+s-r.radii[2], y+s)
+	path += "H" + fmtFloat(x+r.radii[3])
+	path += svgArc(r.radii[3], x, y+s-r.radii[3])
+	path += "V" + fmtFloat(y+r.radii[0])
+	path += svgArc(r.radii[0], x+r.radii[0], y)
+	return path + "Z"
+}
+
+// Returns a clockwise quarter arc of the given radius to the point (x, y),
+// or nothing if the radius is zero.
+func svgArc(radius, x, y float64) string {
+	if radius == 0 {
+		return ""
+	}
+	r := fmtFloat(radius)
+	return fmt.Sprintf("A%s,%s 0 0 1 %s,%s", r, r, fmtFloat(x), fmtFloat(y))
+}
+
+// Formats a coordinate for SVG output with at most four fractional digits.
+func fmtFloat(f float64) string {
+	return fmt.Sprintf("%g", math.Floor(f*10000+0.5)/10000)
+}
+
+// Returns a shape, falling back to a square if it is nil.
+func shapeOrSquare(s Shape) Shape {
+	if s == nil {
+		return SquareShape{}
+	}
+	return s
+}
@@ -0,0 +1,291 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+
+	"github.com/nfnt/resize"
+)
+
+const (
+	// Side of the finder pattern without the separator, measured in modules.
+	finderSide = 7
+
+	// Number of samples per pixel side used to anti-alias styled raster images.
+	styleSamples = 4
+)
+
+// Returns generated QR Code drawn with the given style. Every module
+// is moduleSize x moduleSize pixels, margin is measured in modules.
+func (gen *Generator) StyledImage(style Style, margin, moduleSize uint) (image.Image, error) {
+	return gen.styledImage("StyledImage", style, margin, moduleSize)
+}
+
+// Writes generated QR Code drawn with the given style to w as an image of the given format.
+func (gen *Generator) EncodeStyledImage(w io.Writer, format ImageFormat, style Style, margin, moduleSize uint) error {
+	img, err := gen.styledImage("EncodeStyledImage", style, margin, moduleSize)
+	if err != nil {
+		return err
+	}
+	return encodeImage(w, img, format)
+}
+
+// Returns svg string of generated QR Code drawn with the given style.
+func (gen *Generator) ToStyledSvg(style Style, border int) (string, error) {
+	if border < 0 {
+		return "", generatorErr("ToStyledSvg", "negative border was given")
+	}
+	if border > math.MaxInt64/2 || border*2 > math.MaxInt64-gen.size {
+		return "", generatorErr("ToStyledSvg", "border too large")
+	}
+	body, err := gen.styledSvgBody("ToStyledSvg", style, border)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+		"<!DOCTYPE svg PUBLIC \"-//W3C//DTD SVG 1.1//EN\" \"http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd\">\n"+
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" xmlns:xlink=\"http://www.w3.org/1999/xlink\" version=\"1.1\" "+
+		"viewBox=\"0 0 %d %d\" stroke=\"none\">\n"+
+		"\t<rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n%s</svg>",
+		gen.size+border*2, gen.size+border*2, svgColor(style.background()), body), nil
+}
+
+// Helper method for styled SVG renderers, returns the elements drawing
+// the symbol with the given border, without the background.
+func (gen *Generator) styledSvgBody(method string, style Style, border int) (string, error) {
+	if err := style.validate(method); err != nil {
+		return "", err
+	}
+	style = style.sorted()
+	clear, err := gen.styleLogoArea(method, style)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if style.Fill != nil {
+		fmt.Fprintf(&buf, "\t<defs>\n%s\t</defs>\n", style.Fill.svgDef("fill", gen.size, border))
+	}
+	if style.Logo != nil {
+		href, err := style.Logo.svgHref()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&buf, "\t<image x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" preserveAspectRatio=\"xMidYMid meet\" xlink:href=\"%s\"/>\n",
+			clear.Min.X+border, clear.Min.Y+border, clear.Dx(), clear.Dy(), html.EscapeString(href))
+		// White function modules stay visible above the logo.
+		var light bytes.Buffer
+		for y := clear.Min.Y; y < clear.Max.Y; y++ {
+			for x := clear.Min.X; x < clear.Max.X; x++ {
+				if gen.isFunction[y][x] && !gen.module(x, y) {
+					fmt.Fprintf(&light, "M%d,%dh1v1h-1z", x+border, y+border)
+				}
+			}
+		}
+		if light.Len() > 0 {
+			fmt.Fprintf(&buf, "\t<path d=\"%s\" fill=\"%s\"/>\n", light.String(), svgColor(style.background()))
+		}
+	}
+	buf.WriteString("\t<path d=\"")
+	buf.WriteString(gen.styledSvgPath(style, clear, float64(border)))
+	fmt.Fprintf(&buf, "\" fill=\"%s\" fill-rule=\"evenodd\"/>\n", style.svgForeground())
+	return buf.String(), nil
+}
+
+// Helper method for styled raster renderers.
+func (gen *Generator) styledImage(method string, style Style, margin, moduleSize uint) (image.Image, error) {
+	if moduleSize == 0 {
+		return nil, generatorErr(method, "module size must be positive")
+	}
+	if err := style.validate(method); err != nil {
+		return nil, err
+	}
+	style = style.sorted()
+	clear, err := gen.styleLogoArea(method, style)
+	if err != nil {
+		return nil, err
+	}
+	if style.Logo != nil && style.Logo.Image == nil {
+		return nil, generatorErr(method, "logo has no image")
+	}
+	side := (gen.getSize() + int(margin)*2) * int(moduleSize)
+	img := image.NewRGBA(image.Rect(0, 0, side, side))
+	scale := 1 / float64(moduleSize)
+	background := style.background()
+	for py := 0; py < side; py++ {
+		for px := 0; px < side; px++ {
+			covered := 0
+			for sy := 0; sy < styleSamples; sy++ {
+				for sx := 0; sx < styleSamples; sx++ {
+					fx := (float64(px)+(float64(sx)+0.5)/styleSamples)*scale - float64(margin)
+					fy := (float64(py)+(float64(sy)+0.5)/styleSamples)*scale - float64(margin)
+					if gen.styledDark(style, clear, fx, fy) {
+						covered++
+					}
+				}
+			}
+			if covered == 0 {
+				img.SetRGBA(px, py, background)
+				continue
+			}
+			cx := (float64(px)+0.5)*scale - float64(margin)
+			cy := (float64(py)+0.5)*scale - float64(margin)
+			img.SetRGBA(px, py, blendColors(style.foregroundAt(cx, cy, gen.size), background,
+				float64(covered)/(styleSamples*styleSamples)))
+		}
+	}
+	if style.Logo != nil {
+		gen.drawLogo(img, style, clear, int(margin), int(moduleSize))
+	}
+	return img, nil
+}
+
+// Returns the area in modules cleared by the logo of the given style,
+// or an empty rectangle if there is no logo.
+func (gen *Generator) styleLogoArea(method string, style Style) (image.Rectangle, error) {
+	if style.Logo == nil {
+		return image.Rectangle{}, nil
+	}
+	return gen.logoArea(method, *style.Logo)
+}
+
+// Draws the logo of the given style scaled to fit the cleared area,
+// then redraws function modules of that area on top of it.
+func (gen *Generator) drawLogo(img *image.RGBA, style Style, clear image.Rectangle, margin, moduleSize int) {
+	logo := style.Logo.Image
+	pixels := modulePixels(clear, margin, moduleSize)
+	bounds := logo.Bounds()
+	width, height := pixels.Dx(), pixels.Dy()
+	if bounds.Dx() > bounds.Dy() {
+		height = height * bounds.Dy() / bounds.Dx()
+	} else {
+		width = width * bounds.Dx() / bounds.Dy()
+	}
+	if width > 0 && height > 0 {
+		scaled := resize.Resize(uint(width), uint(height), logo, resize.Bilinear)
+		min := pixels.Min.Add(image.Pt((pixels.Dx()-width)/2, (pixels.Dy()-height)/2))
+		draw.Draw(img, image.Rectangle{Min: min, Max: min.Add(image.Pt(width, height))}, scaled, scaled.Bounds().Min, draw.Over)
+	}
+	for y := clear.Min.Y; y < clear.Max.Y; y++ {
+		for x := clear.Min.X; x < clear.Max.X; x++ {
+			if !gen.isFunction[y][x] {
+				continue
+			}
+			c := style.background()
+			if gen.module(x, y) {
+				c = style.foregroundAt(float64(x)+0.5, float64(y)+0.5, gen.size)
+			}
+			cell := modulePixels(image.Rect(x, y, x+1, y+1), margin, moduleSize)
+			draw.Draw(img, cell, &image.Uniform{C: c}, image.ZP, draw.Src)
+		}
+	}
+}
+
+// Returns the pixel rectangle of the given rectangle of modules.
+func modulePixels(r image.Rectangle, margin, moduleSize int) image.Rectangle {
+	r = r.Add(image.Pt(margin, margin))
+	return image.Rect(r.Min.X*moduleSize, r.Min.Y*moduleSize, r.Max.X*moduleSize, r.Max.Y*moduleSize)
+}
+
+// Returns SVG path data of all black modules drawn with the given style,
+// offset by border modules. Finder rings are drawn as an outer and an inner
+// outline, so the path has to be filled using the even-odd rule.
+func (gen *Generator) styledSvgPath(style Style, clear image.Rectangle, border float64) string {
+	var buf bytes.Buffer
+	outer, center := shapeOrSquare(style.FinderOuter), shapeOrSquare(style.FinderCenter)
+	for _, origin := range gen.finderOrigins() {
+		x, y := float64(origin.X)+border, float64(origin.Y)+border
+		buf.WriteString(outer.SvgPath(x, y, finderSide, Neighbours{}))
+		buf.WriteString(outer.SvgPath(x+1, y+1, finderSide-2, Neighbours{}))
+		buf.WriteString(center.SvgPath(x+2, y+2, finderSide-4, Neighbours{}))
+	}
+	module := shapeOrSquare(style.Module)
+	for y := 0; y < gen.size; y++ {
+		for x := 0; x < gen.size; x++ {
+			if _, _, ok := gen.finderOrigin(x, y); ok || !gen.module(x, y) {
+				continue
+			}
+			if !gen.isFunction[y][x] && image.Pt(x, y).In(clear) {
+				continue
+			}
+			if gen.isFunction[y][x] {
+				fmt.Fprintf(&buf, "M%s,%sh1v1h-1z", fmtFloat(float64(x)+border), fmtFloat(float64(y)+border))
+			} else {
+				buf.WriteString(module.SvgPath(float64(x)+border, float64(y)+border, 1, gen.dataNeighbours(x, y)))
+			}
+		}
+	}
+	return buf.String()
+}
+
+// Returns true if the point (fx, fy), measured in modules from the top left
+// corner of the symbol, is covered by black when drawn with the given style.
+// Data modules inside the clear area are left white.
+func (gen *Generator) styledDark(style Style, clear image.Rectangle, fx, fy float64) bool {
+	x, y := int(math.Floor(fx)), int(math.Floor(fy))
+	if x < 0 || y < 0 || x >= gen.size || y >= gen.size {
+		return false
+	}
+	if ox, oy, ok := gen.finderOrigin(x, y); ok {
+		lx, ly := fx-float64(ox), fy-float64(oy)
+		outer, center := shapeOrSquare(style.FinderOuter), shapeOrSquare(style.FinderCenter)
+		if outer.Contains(lx, ly, finderSide, Neighbours{}) && !outer.Contains(lx-1, ly-1, finderSide-2, Neighbours{}) {
+			return true
+		}
+		return center.Contains(lx-2, ly-2, finderSide-4, Neighbours{})
+	}
+	if !gen.module(x, y) {
+		return false
+	}
+	if gen.isFunction[y][x] {
+		return true
+	}
+	if image.Pt(x, y).In(clear) {
+		return false
+	}
+	return shapeOrSquare(style.Module).Contains(fx-float64(x), fy-float64(y), 1, gen.dataNeighbours(x, y))
+}
+
+// Returns the top left corners of the three finder patterns.
+func (gen *Generator) finderOrigins() []image.Point {
+	return []image.Point{{0, 0}, {gen.size - finderSide, 0}, {0, gen.size - finderSide}}
+}
+
+// Returns the top left corner of the finder pattern (without the separator)
+// the module at (x, y) belongs to.
+func (gen *Generator) finderOrigin(x, y int) (int, int, bool) {
+	for _, origin := range gen.finderOrigins() {
+		if origin.X <= x && x < origin.X+finderSide && origin.Y <= y && y < origin.Y+finderSide {
+			return origin.X, origin.Y, true
+		}
+	}
+	return 0, 0, false
+}
+
+// Returns which of the adjacent modules of the module at (x, y) are black.
+// Finder patterns are styled separately and never count as neighbours.
+func (gen *Generator) dataNeighbours(x, y int) Neighbours {
+	dark := func(x, y int) bool {
+		_, _, inFinder := gen.finderOrigin(x, y)
+		return gen.getModule(x, y) && !inFinder
+	}
+	return Neighbours{Top: dark(x, y-1), Right: dark(x+1, y), Bottom: dark(x, y+1), Left: dark(x-1, y)}
+}
+
+// Returns a mix of the dark and light colors, where coverage in the range [0, 1]
+// is the fraction of dark.
+func blendColors(dark, light color.RGBA, coverage float64) color.RGBA {
+	mix := func(d, l uint8) uint8 {
+		return uint8(math.Floor(float64(d)*coverage + float64(l)*(1-coverage) + 0.5))
+	}
+	return color.RGBA{R: mix(dark.R, light.R), G: mix(dark.G, light.G), B: mix(dark.B, light.B), A: mix(dark.A, light.A)}
+}
@@ -0,0 +1,116 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func Test_StyledImageDefault(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	img, err := gen.StyledImage(Style{}, 2, 3)
+	if err != nil {
+		test.Fatalf("style_renderer.Test_StyledImageDefault:\n\tunexpected error -> %s", err)
+	}
+	if img.Bounds().Dx() != 75 || img.Bounds().Dy() != 75 {
+		test.Fatalf("style_renderer.Test_StyledImageDefault:\n\tunexpected bounds -> %v", img.Bounds())
+	}
+	for y := 0; y < 75; y++ {
+		for x := 0; x < 75; x++ {
+			expected := gen.getModule(x/3-2, y/3-2)
+			if actual := isDarkColor(img.At(x, y)); actual != expected {
+				test.Fatalf(
+					"style_renderer.Test_StyledImageDefault:\n\tactual pixel (%d, %d) -> %t\n is not equal to\n\texpected pixel -> %t",
+					x, y, actual, expected,
+				)
+			}
+		}
+	}
+}
+
+func Test_StyledImageShapes(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	img, err := gen.StyledImage(Style{Module: CircleShape{}, FinderCenter: CircleShape{}}, 0, 10)
+	if err != nil {
+		test.Fatalf("style_renderer.Test_StyledImageShapes:\n\tunexpected error -> %s", err)
+	}
+	// Finder ring stays square and crisp.
+	if img.At(0, 0) != (color.RGBA{A: 255}) {
+		test.Errorf("style_renderer.Test_StyledImageShapes:\n\tfinder corner is not black -> %v", img.At(0, 0))
+	}
+	// Finder center is a circle.
+	if !isDarkColor(img.At(35, 35)) || isDarkColor(img.At(20, 20)) {
+		test.Errorf("style_renderer.Test_StyledImageShapes:\n\tfinder center is not a circle")
+	}
+	for y := 0; y < gen.size; y++ {
+		for x := 0; x < gen.size; x++ {
+			if gen.isFunction[y][x] || !gen.module(x, y) {
+				continue
+			}
+			if !isDarkColor(img.At(x*10+5, y*10+5)) || isDarkColor(img.At(x*10, y*10)) {
+				test.Errorf("style_renderer.Test_StyledImageShapes:\n\tdata module (%d, %d) is not a circle", x, y)
+			}
+		}
+	}
+	if _, err = gen.StyledImage(Style{}, 0, 0); err == nil {
+		test.Errorf("style_renderer.Test_StyledImageShapes:\n\tzero module size was accepted")
+	}
+}
+
+func Test_ToStyledSvg(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("HELLO WORLD")
+	actual, err := gen.ToStyledSvg(Style{Module: LiquidShape{}}, 4)
+	if err != nil {
+		test.Fatalf("style_renderer.Test_ToStyledSvg:\n\tunexpected error -> %s", err)
+	}
+	for _, expected := range []string{
+		"viewBox=\"0 0 29 29\"",
+		"fill-rule=\"evenodd\"",
+		// Outer ring, hole and center of the top left finder pattern.
+		"d=\"M4,4H11V11H4V4ZM5,5H10V10H5V5ZM6,6H9V9H6V6Z",
+	} {
+		if !strings.Contains(actual, expected) {
+			test.Errorf("style_renderer.Test_ToStyledSvg:\n\tsvg -> %s\n does not contain\n\texpected -> %s", actual, expected)
+		}
+	}
+	if _, err = gen.ToStyledSvg(Style{}, -1); err == nil {
+		test.Errorf("style_renderer.Test_ToStyledSvg:\n\tnegative border was accepted")
+	}
+}
+
+var dataNeighbours_TestData = []struct {
+	x, y     int
+	expected Neighbours
+}{
+	{x: 10, y: 10, expected: Neighbours{Right: true, Bottom: true}},
+	{x: 11, y: 11, expected: Neighbours{Top: true, Left: true}},
+	{x: 12, y: 10, expected: Neighbours{Left: true}},
+	// Black finder module on the left is not a neighbour.
+	{x: 7, y: 2, expected: Neighbours{}},
+}
+
+func Test_dataNeighbours(test *testing.T) {
+	gen := Generator{size: 21, modules: make([][]bool, 21)}
+	for y := range gen.modules {
+		gen.modules[y] = make([]bool, 21)
+	}
+	gen.modules[2][6] = true
+	gen.modules[10][11] = true
+	gen.modules[11][10] = true
+	for i, data := range dataNeighbours_TestData {
+		actual := gen.dataNeighbours(data.x, data.y)
+		if actual != data.expected {
+			test.Errorf(
+				"style_renderer.Test_dataNeighbours[%d]:\n\tactual neighbours -> %v\n is not equal to\n\texpected neighbours -> %v",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import "testing"
+
+var ShapeContains_TestData = []struct {
+	shape    Shape
+	x, y     float64
+	side     float64
+	n        Neighbours
+	expected bool
+}{
+	{shape: SquareShape{}, x: 0.01, y: 0.01, side: 1, expected: true},
+	{shape: SquareShape{}, x: 1, y: 0.5, side: 1, expected: false},
+	{shape: CircleShape{}, x: 0.5, y: 0.5, side: 1, expected: true},
+	{shape: CircleShape{}, x: 0.05, y: 0.05, side: 1, expected: false},
+	{shape: CircleShape{}, x: 0.5, y: 0.01, side: 1, expected: true},
+	{shape: CircleShape{}, x: 3.5, y: 6.9, side: 7, expected: true},
+	{shape: CircleShape{}, x: 0.5, y: 6.5, side: 7, expected: false},
+	{shape: RoundedShape{Radius: 0.25}, x: 0.05, y: 0.05, side: 1, expected: false},
+	{shape: RoundedShape{Radius: 0.25}, x: 0.2, y: 0.2, side: 1, expected: true},
+	{shape: RoundedShape{Radius: 0.25}, x: 0.95, y: 0.5, side: 1, expected: true},
+	{shape: RoundedShape{Radius: 0}, x: 0.01, y: 0.99, side: 1, expected: true},
+	{shape: LiquidShape{}, x: 0.05, y: 0.05, side: 1, expected: false},
+	{shape: LiquidShape{}, x: 0.05, y: 0.05, side: 1, n: Neighbours{Top: true}, expected: true},
+	{shape: LiquidShape{}, x: 0.05, y: 0.95, side: 1, n: Neighbours{Top: true}, expected: false},
+	{shape: LiquidShape{}, x: 0.95, y: 0.95, side: 1, n: Neighbours{Right: true}, expected: true},
+}
+
+func Test_ShapeContains(test *testing.T) {
+	for i, data := range ShapeContains_TestData {
+		actual := data.shape.Contains(data.x, data.y, data.side, data.n)
+		if actual != data.expected {
+			test.Errorf(
+				"style.Test_ShapeContains[%d]:\n\tactual -> %t\n is not equal to\n\texpected -> %t",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+var ShapeSvgPath_TestData = []struct {
+	shape    Shape
+	x, y     float64
+	side     float64
+	n        Neighbours
+	expected string
+}{
+	{
+		shape:    SquareShape{},
+		x:        2,
+		y:        3,
+		side:     1,
+		expected: "M2,3H3V4H2V3Z",
+	},
+	{
+		shape:    CircleShape{},
+		x:        0,
+		y:        0,
+		side:     1,
+		expected: "M0.5,0H0.5A0.5,0.5 0 0 1 1,0.5V0.5A0.5,0.5 0 0 1 0.5,1H0.5A0.5,0.5 0 0 1 0,0.5V0.5A0.5,0.5 0 0 1 0.5,0Z",
+	},
+	{
+		shape:    RoundedShape{Radius: 0.2},
+		x:        1,
+		y:        1,
+		side:     5,
+		expected: "M2,1H5A1,1 0 0 1 6,2V5A1,1 0 0 1 5,6H2A1,1 0 0 1 1,5V2A1,1 0 0 1 2,1Z",
+	},
+	{
+		shape:    LiquidShape{},
+		x:        0,
+		y:        0,
+		side:     1,
+		n:        Neighbours{Left: true, Bottom: true},
+		expected: "M0,0H0.5A0.5,0.5 0 0 1 1,0.5V1H0V0Z",
+	},
+}
+
+func Test_ShapeSvgPath(test *testing.T) {
+	for i, data := range ShapeSvgPath_TestData {
+		actual := data.shape.SvgPath(data.x, data.y, data.side, data.n)
+		if actual != data.expected {
+			test.Errorf(
+				"style.Test_ShapeSvgPath[%d]:\n\tactual path -> %s\n is not equal to\n\texpected path -> %s",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+func Test_shapeOrSquare(test *testing.T) {
+	if _, ok := shapeOrSquare(nil).(SquareShape); !ok {
+		test.Errorf("style.Test_shapeOrSquare:\n\tnil shape is not replaced with a square")
+	}
+	if _, ok := shapeOrSquare(CircleShape{}).(CircleShape); !ok {
+		test.Errorf("style.Test_shapeOrSquare:\n\tnon-nil shape is replaced")
+	}
+}
@@ -0,0 +1,127 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"bytes"
+	"io"
+)
+
+// Unicode quadrant block characters indexed by a 4-bit mask of filled quarters:
+// bit 0 - upper left, bit 1 - upper right, bit 2 - lower left, bit 3 - lower right.
+var quadrantBlocks = [16]rune{
+	' ', '▘', '▝', '▀', '▖', '▌', '▞', '▛',
+	'▗', '▚', '▐', '▜', '▄', '▙', '▟', '█',
+}
+
+// Braille dot bits indexed by [row][column] of a 2x4 cell, starting from U+2800.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+const brailleBlank = '⠀'
+
+// Writes generated QR Code to w as plain ASCII art. Every black module is written
+// as dark and every white module as light, one text line per row of modules.
+// Two characters per module, e.g. "##" and "  ", keep the code roughly square
+// in monospace fonts.
+func (gen *Generator) DrawASCII(w io.Writer, margin int, dark, light string) error {
+	if margin < 0 {
+		return generatorErr("DrawASCII", "margin is negative")
+	}
+	if len(dark) == 0 || len(dark) != len(light) {
+		return generatorErr("DrawASCII", "dark and light strings must be non-empty and of equal length")
+	}
+	modules := gen.GetModules()
+	var buf bytes.Buffer
+	for y := -margin; y < len(modules)+margin; y++ {
+		for x := -margin; x < len(modules)+margin; x++ {
+			if matrixModule(modules, x, y) {
+				buf.WriteString(dark)
+			} else {
+				buf.WriteString(light)
+			}
+		}
+		buf.WriteByte('\n')
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Writes generated QR Code to w using Unicode quadrant block characters,
+// packing 2x2 modules into every character. Black modules are drawn as filled
+// quarters; set invert to draw white modules filled instead, which is what
+// terminals with a dark background need.
+func (gen *Generator) DrawQuadrants(w io.Writer, margin int, invert bool) error {
+	if margin < 0 {
+		return generatorErr("DrawQuadrants", "margin is negative")
+	}
+	modules := gen.GetModules()
+	var buf bytes.Buffer
+	for y := -margin; y < len(modules)+margin; y += 2 {
+		for x := -margin; x < len(modules)+margin; x += 2 {
+			index := 0
+			for i := 0; i < 4; i++ {
+				if textModule(modules, x+i%2, y+i/2, margin, invert) {
+					index |= 1 << uint(i)
+				}
+			}
+			buf.WriteRune(quadrantBlocks[index])
+		}
+		buf.WriteByte('\n')
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Writes generated QR Code to w using Unicode Braille patterns, packing 2x4
+// modules into every character. Black modules are drawn as raised dots; set
+// invert to raise dots for white modules instead.
+func (gen *Generator) DrawBraille(w io.Writer, margin int, invert bool) error {
+	if margin < 0 {
+		return generatorErr("DrawBraille", "margin is negative")
+	}
+	modules := gen.GetModules()
+	var buf bytes.Buffer
+	for y := -margin; y < len(modules)+margin; y += 4 {
+		for x := -margin; x < len(modules)+margin; x += 2 {
+			char := rune(brailleBlank)
+			for row := 0; row < 4; row++ {
+				for col := 0; col < 2; col++ {
+					if textModule(modules, x+col, y+row, margin, invert) {
+						char |= brailleDots[row][col]
+					}
+				}
+			}
+			buf.WriteRune(char)
+		}
+		buf.WriteByte('\n')
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Returns whether the module at the given coordinates has to be filled by
+// a packing text renderer. Modules outside of the margin are never filled,
+// so that the last character row or column is not padded with an inverted strip.
+//
+// Helper function for text renderers.
+func textModule(modules [][]bool, x, y, margin int, invert bool) bool {
+	if x >= len(modules)+margin || y >= len(modules)+margin {
+		return false
+	}
+	return xor(matrixModule(modules, x, y), invert)
+}
+
+// Returns the module of a matrix returned by GetModules at the given coordinates,
+// or false (white) if the coordinates are out of bounds.
+//
+// Helper function for renderers.
+func matrixModule(modules [][]bool, x, y int) bool {
+	return 0 <= y && y < len(modules) && 0 <= x && x < len(modules[y]) && modules[y][x]
+}
@@ -0,0 +1,175 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"bytes"
+	"testing"
+)
+
+var textRendererGen = Generator{
+	size: 3,
+	modules: [][]bool{
+		{true, false, true},
+		{false, true, false},
+		{true, true, false},
+	},
+}
+
+var DrawASCII_TestData = []struct {
+	margin   int
+	dark     string
+	light    string
+	expected string
+}{
+	{
+		margin:   0,
+		dark:     "##",
+		light:    "..",
+		expected: "##..##\n..##..\n####..\n",
+	},
+	{
+		margin:   1,
+		dark:     "#",
+		light:    ".",
+		expected: ".....\n.#.#.\n..#..\n.##..\n.....\n",
+	},
+}
+
+func Test_DrawASCII(test *testing.T) {
+	for i, data := range DrawASCII_TestData {
+		var buf bytes.Buffer
+		err := textRendererGen.DrawASCII(&buf, data.margin, data.dark, data.light)
+		if err != nil {
+			test.Errorf("text_renderer.Test_DrawASCII[%d]:\n\tunexpected error -> %s", i, err)
+		}
+		if actual := buf.String(); actual != data.expected {
+			test.Errorf(
+				"text_renderer.Test_DrawASCII[%d]:\n\tactual text -> %q\n is not equal to\n\texpected text -> %q",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+var DrawASCIIErr_TestData = []struct {
+	margin   int
+	dark     string
+	light    string
+	expected error
+}{
+	{
+		margin:   -1,
+		dark:     "##",
+		light:    "  ",
+		expected: generatorErr("DrawASCII", "margin is negative"),
+	},
+	{
+		margin:   0,
+		dark:     "##",
+		light:    " ",
+		expected: generatorErr("DrawASCII", "dark and light strings must be non-empty and of equal length"),
+	},
+	{
+		margin:   0,
+		dark:     "",
+		light:    "",
+		expected: generatorErr("DrawASCII", "dark and light strings must be non-empty and of equal length"),
+	},
+}
+
+func Test_DrawASCIIErr(test *testing.T) {
+	for i, data := range DrawASCIIErr_TestData {
+		var buf bytes.Buffer
+		actual := textRendererGen.DrawASCII(&buf, data.margin, data.dark, data.light)
+		if actual == nil || actual.Error() != data.expected.Error() {
+			test.Errorf(
+				"text_renderer.Test_DrawASCIIErr[%d]:\n\tactual err -> %v\n is not equal to\n\texpected err -> %v",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+var DrawQuadrants_TestData = []struct {
+	margin   int
+	invert   bool
+	expected string
+}{
+	{
+		margin:   0,
+		invert:   false,
+		expected: "▚▘\n▀ \n",
+	},
+	{
+		margin:   0,
+		invert:   true,
+		expected: "▞▖\n ▘\n",
+	},
+	{
+		margin:   1,
+		invert:   false,
+		expected: "▗▗ \n▗▌ \n   \n",
+	},
+}
+
+func Test_DrawQuadrants(test *testing.T) {
+	for i, data := range DrawQuadrants_TestData {
+		var buf bytes.Buffer
+		err := textRendererGen.DrawQuadrants(&buf, data.margin, data.invert)
+		if err != nil {
+			test.Errorf("text_renderer.Test_DrawQuadrants[%d]:\n\tunexpected error -> %s", i, err)
+		}
+		if actual := buf.String(); actual != data.expected {
+			test.Errorf(
+				"text_renderer.Test_DrawQuadrants[%d]:\n\tactual text -> %q\n is not equal to\n\texpected text -> %q",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+var DrawBraille_TestData = []struct {
+	margin   int
+	invert   bool
+	expected string
+}{
+	{
+		margin:   0,
+		invert:   false,
+		expected: "⠵⠁\n",
+	},
+	{
+		margin:   0,
+		invert:   true,
+		expected: "⠊⠆\n",
+	},
+}
+
+func Test_DrawBraille(test *testing.T) {
+	for i, data := range DrawBraille_TestData {
+		var buf bytes.Buffer
+		err := textRendererGen.DrawBraille(&buf, data.margin, data.invert)
+		if err != nil {
+			test.Errorf("text_renderer.Test_DrawBraille[%d]:\n\tunexpected error -> %s", i, err)
+		}
+		if actual := buf.String(); actual != data.expected {
+			test.Errorf(
+				"text_renderer.Test_DrawBraille[%d]:\n\tactual text -> %q\n is not equal to\n\texpected text -> %q",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+func Test_TextRenderersErr(test *testing.T) {
+	var buf bytes.Buffer
+	if err := textRendererGen.DrawQuadrants(&buf, -1, false); err == nil {
+		test.Errorf("text_renderer.Test_TextRenderersErr:\n\tDrawQuadrants accepted negative margin")
+	}
+	if err := textRendererGen.DrawBraille(&buf, -1, false); err == nil {
+		test.Errorf("text_renderer.Test_TextRenderersErr:\n\tDrawBraille accepted negative margin")
+	}
+}
@@ -0,0 +1,81 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr // import "github.com/YuriyLisovskiy/qrcode/qr"
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"math"
+)
+
+// Writes generated QR Code to w as a TikZ picture, ready to be included into
+// a LaTeX document with \input. Black modules are drawn by a single \fill path
+// of merged rectangles. Every module is moduleSize x moduleSize points (pt),
+// margin is measured in modules and reserves the quiet zone in the bounding box.
+func (gen *Generator) DrawTikz(w io.Writer, margin uint, moduleSize float64) error {
+	if moduleSize <= 0 || math.IsInf(moduleSize, 0) || math.IsNaN(moduleSize) {
+		return generatorErr("DrawTikz", "module size must be positive")
+	}
+	modules := gen.GetModules()
+	side := float64(len(modules)+int(margin)*2) * moduleSize
+	pt := func(v int) string {
+		return fmtFloat(float64(v+int(margin))*moduleSize) + "pt"
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "\\begin{tikzpicture}[yscale=-1]\n\\useasboundingbox (0pt,0pt) rectangle (%spt,%spt);\n\\fill",
+		fmtFloat(side), fmtFloat(side))
+	for _, r := range mergedRectangles(modules) {
+		fmt.Fprintf(&buf, "\n\t(%s,%s) rectangle (%s,%s)", pt(r.Min.X), pt(r.Min.Y), pt(r.Max.X), pt(r.Max.Y))
+	}
+	buf.WriteString(";\n\\end{tikzpicture}\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Covers black modules of a matrix returned by GetModules with rectangles,
+// row by row: every run of black modules is extended down as long as the rows
+// below continue it with black modules which are not covered yet.
+//
+// Helper function for vector renderers.
+func mergedRectangles(modules [][]bool) []image.Rectangle {
+	covered := make([][]bool, len(modules))
+	for y := range covered {
+		covered[y] = make([]bool, len(modules[y]))
+	}
+	free := func(x, y int) bool {
+		return y < len(modules) && x < len(modules[y]) && modules[y][x] && !covered[y][x]
+	}
+	var rects []image.Rectangle
+	for y := range modules {
+		for x := range modules[y] {
+			if !free(x, y) {
+				continue
+			}
+			width := 1
+			for free(x+width, y) {
+				width++
+			}
+			height := 1
+			for ; ; height++ {
+				row := true
+				for i := 0; i < width && row; i++ {
+					row = free(x+i, y+height)
+				}
+				if !row {
+					break
+				}
+			}
+			for j := 0; j < height; j++ {
+				for i := 0; i < width; i++ {
+					covered[y+j][x+i] = true
+				}
+			}
+			rects = append(rects, image.Rect(x, y, x+width, y+height))
+		}
+	}
+	return rects
+}
@@ -0,0 +1,113 @@
+//  Copyright (c) 2018 Yuriy Lisovskiy
+//  Distributed under the Apache License Version 2.0,
+//  see the accompanying file LICENSE or https://opensource.org/licenses/Apache-2.0
+
+package qr
+
+import (
+	"bytes"
+	"image"
+	"reflect"
+	"testing"
+)
+
+var mergedRectangles_TestData = []struct {
+	modules  [][]bool
+	expected []image.Rectangle
+}{
+	{
+		modules:  [][]bool{{false, false}, {false, false}},
+		expected: nil,
+	},
+	{
+		modules:  [][]bool{{true, true, true}, {true, true, true}, {false, true, true}},
+		expected: []image.Rectangle{image.Rect(0, 0, 3, 2), image.Rect(1, 2, 3, 3)},
+	},
+	{
+		modules: [][]bool{{true, false, true}, {false, true, false}, {true, true, false}},
+		expected: []image.Rectangle{
+			image.Rect(0, 0, 1, 1), image.Rect(2, 0, 3, 1), image.Rect(1, 1, 2, 3), image.Rect(0, 2, 1, 3),
+		},
+	},
+}
+
+func Test_mergedRectangles(test *testing.T) {
+	for i, data := range mergedRectangles_TestData {
+		if actual := mergedRectangles(data.modules); !reflect.DeepEqual(actual, data.expected) {
+			test.Errorf(
+				"tikz_renderer.Test_mergedRectangles[%d]:\n\tactual -> %v\n is not equal to\n\texpected -> %v",
+				i, actual, data.expected,
+			)
+		}
+	}
+}
+
+func Test_mergedRectanglesCover(test *testing.T) {
+	gen := Generator{}
+	gen = gen.EncodeText("https://github.com/YuriyLisovskiy/qrcode")
+	modules := gen.GetModules()
+	covered := make([][]bool, len(modules))
+	for y := range covered {
+		covered[y] = make([]bool, len(modules))
+	}
+	for _, r := range mergedRectangles(modules) {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				if covered[y][x] {
+					test.Fatalf("tikz_renderer.Test_mergedRectanglesCover:\n\tmodule (%d, %d) is covered twice", x, y)
+				}
+				covered[y][x] = true
+			}
+		}
+	}
+	if !reflect.DeepEqual(covered, modules) {
+		test.Errorf("tikz_renderer.Test_mergedRectanglesCover:\n\tcovered modules are not equal to black modules")
+	}
+}
+
+var DrawTikz_TestData = []struct {
+	margin     uint
+	moduleSize float64
+	expected   string
+}{
+	{
+		margin:     0,
+		moduleSize: 1,
+		expected: "\\begin{tikzpicture}[yscale=-1]\n" +
+			"\\useasboundingbox (0pt,0pt) rectangle (3pt,3pt);\n" +
+			"\\fill\n" +
+			"\t(0pt,0pt) rectangle (1pt,1pt)\n" +
+			"\t(2pt,0pt) rectangle (3pt,1pt)\n" +
+			"\t(1pt,1pt) rectangle (2pt,3pt)\n" +
+			"\t(0pt,2pt) rectangle (1pt,3pt);\n" +
+			"\\end{tikzpicture}\n",
+	},
+	{
+		margin:     2,
+		moduleSize: 1.5,
+		expected: "\\begin{tikzpicture}[yscale=-1]\n" +
+			"\\useasboundingbox (0pt,0pt) rectangle (10.5pt,10.5pt);\n" +
+			"\\fill\n" +
+			"\t(3pt,3pt) rectangle (4.5pt,4.5pt)\n" +
+			"\t(6pt,3pt) rectangle (7.5pt,4.5pt)\n" +
+			"\t(4.5pt,4.5pt) rectangle (6pt,7.5pt)\n" +
+			"\t(3pt,6pt) rectangle (4.5pt,7.5pt);\n" +
+			"\\end{tikzpicture}\n",
+	},
+}
+
+func Test_DrawTikz(test *testing.T) {
+	for i, data := range DrawTikz_TestData {
+		var buf bytes.Buffer
+		err := textRendererGen.DrawTikz(&buf, data.margin, data.moduleSize)
+		if actual := buf.String(); err != nil || actual != data.expected {
+			test.Errorf(
+				"tikz_renderer.Test_DrawTikz[%d]:\n\tactual -> %s (err: %v)\n is not equal to\n\texpected -> %s",
+				i, actual, err, data.expected,
+			)
+		}
+	}
+	if err := textRendererGen.DrawTikz(&bytes.Buffer{}, 0, 0); err == nil {
+		test.Errorf("tikz_renderer.Test_DrawTikz:\n\tzero module size is accepted")
+	}
+}